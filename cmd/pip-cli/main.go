@@ -26,6 +26,7 @@ Commands:
   list        List installed packages
   show        Show package information
   freeze      Output installed packages in requirements format
+  check       Verify installed packages have compatible dependencies
   venv        Virtual environment operations
   project     Project operations
   version     Show version information
@@ -36,15 +37,19 @@ Global Options:
   -verbose            Enable verbose logging
   -python string      Path to Python executable
   -pip string         Path to pip executable
+  -dry-run            Print the commands and file changes instead of making them
 
 Examples:
   pip-cli install requests
   pip-cli install requests click flask
   pip-cli install "requests>=2.25.0"
+  pip-cli install -preview requests flask
   pip-cli venv create ./myenv
   pip-cli project init ./myproject
   pip-cli list
   pip-cli show requests
+  pip-cli check
+  pip-cli -dry-run install requests
 
 For more information about a command, use: pip-cli help <command>
 `
@@ -55,6 +60,7 @@ var (
 	verboseFlag = flag.Bool("verbose", false, "Enable verbose logging")
 	pythonFlag  = flag.String("python", "", "Path to Python executable")
 	pipFlag     = flag.String("pip", "", "Path to pip executable")
+	dryRunFlag  = flag.Bool("dry-run", false, "Print planned changes instead of making them")
 )
 
 func main() {
@@ -77,6 +83,7 @@ func main() {
 		PythonPath:  *pythonFlag,
 		PipPath:     *pipFlag,
 		Environment: make(map[string]string),
+		DryRun:      *dryRunFlag,
 	}
 
 	if *verboseFlag {
@@ -115,6 +122,8 @@ func main() {
 		handleShow(manager, args)
 	case "freeze":
 		handleFreeze(manager, args)
+	case "check":
+		handleCheck(manager, args)
 	case "venv":
 		handleVenv(manager, args)
 	case "project":
@@ -128,6 +137,21 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
+
+	if *dryRunFlag {
+		printPlan(manager.Plan())
+	}
+}
+
+// printPlan prints the changes recorded in dry-run mode
+func printPlan(plan *pip.Plan) {
+	fmt.Println()
+	if len(plan.Steps) == 0 {
+		fmt.Println("Dry run: no changes planned")
+		return
+	}
+	fmt.Println("Dry run: no changes were made. Planned steps:")
+	fmt.Print(plan.String())
 }
 
 // isVersionSpec checks if a string looks like a version specification
@@ -153,11 +177,16 @@ func isVersionSpec(s string) bool {
 	return false
 }
 
-func handleInstall(manager pip.PipManager, args []string) {
+func handleInstall(manager *pip.Manager, args []string) {
+	flags := flag.NewFlagSet("install", flag.ExitOnError)
+	preview := flags.Bool("preview", false, "Show what would be installed, upgraded or downgraded without installing")
+	flags.Parse(args)
+	args = flags.Args()
+
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: package name required\n")
-		fmt.Fprintf(os.Stderr, "Usage: pip-cli install <package1> [package2] ...\n")
-		fmt.Fprintf(os.Stderr, "       pip-cli install <package> <version>\n")
+		fmt.Fprintf(os.Stderr, "Usage: pip-cli install [-preview] <package1> [package2] ...\n")
+		fmt.Fprintf(os.Stderr, "       pip-cli install [-preview] <package> <version>\n")
 		os.Exit(1)
 	}
 
@@ -184,6 +213,11 @@ func handleInstall(manager pip.PipManager, args []string) {
 		}
 	}
 
+	if *preview {
+		handleInstallPreview(manager, packages)
+		return
+	}
+
 	if len(packages) == 1 {
 		versionStr := ""
 		if packages[0].Version != "" {
@@ -231,6 +265,48 @@ func handleInstall(manager pip.PipManager, args []string) {
 	}
 }
 
+// handleInstallPreview prints what installing packages would change
+func handleInstallPreview(manager *pip.Manager, packages []*pip.PackageSpec) {
+	fmt.Println("Resolving packages...")
+
+	preview, err := manager.PreviewInstall(packages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Preview failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	fmt.Printf("%-10s %-30s %-15s %s\n", "Action", "Package", "Installed", "New")
+	fmt.Printf("%s\n", strings.Repeat("-", 80))
+
+	groups := []struct {
+		action  string
+		changes []*pip.PackageChange
+	}{
+		{"install", preview.Install},
+		{"upgrade", preview.Upgrade},
+		{"downgrade", preview.Downgrade},
+		{"unchanged", preview.Unchanged},
+	}
+	for _, group := range groups {
+		for _, change := range group.changes {
+			from := change.From
+			if from == "" {
+				from = "-"
+			}
+			fmt.Printf("%-10s %-30s %-15s %s\n", group.action, change.Name, from, change.To)
+		}
+	}
+
+	fmt.Println()
+	if !preview.HasChanges() {
+		fmt.Println("Nothing to install: all packages are already at the resolved versions")
+		return
+	}
+	fmt.Printf("%d to install, %d to upgrade, %d to downgrade, %d unchanged\n",
+		len(preview.Install), len(preview.Upgrade), len(preview.Downgrade), len(preview.Unchanged))
+}
+
 func handleUninstall(manager pip.PipManager, args []string) {
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: package name required\n")
@@ -253,10 +329,43 @@ func handleUninstall(manager pip.PipManager, args []string) {
 	fmt.Printf("✓ Package %s uninstalled successfully (took %v)\n", packageName, duration)
 }
 
-func handleList(manager pip.PipManager, args []string) {
+// stringList is a repeatable string flag that also accepts comma-separated values
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+func handleList(manager *pip.Manager, args []string) {
+	opts := &pip.ListOptions{}
+	var exclude, paths stringList
+
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	flags.BoolVar(&opts.NotRequired, "not-required", false, "List packages that are not dependencies of installed packages")
+	flags.BoolVar(&opts.User, "user", false, "Only list packages installed in the user site")
+	flags.BoolVar(&opts.Local, "local", false, "Do not list globally installed packages inside a virtualenv")
+	flags.BoolVar(&opts.Editable, "editable", false, "Only list editable installs")
+	flags.BoolVar(&opts.ExcludeEditable, "exclude-editable", false, "Do not list editable installs")
+	flags.Var(&exclude, "exclude", "Exclude a package (repeatable or comma-separated)")
+	flags.Var(&paths, "path", "Only list packages installed in this directory (repeatable)")
+	flags.BoolVar(&opts.Verbose, "v", false, "Show location and installer")
+	flags.Parse(args)
+
+	opts.Exclude = exclude
+	opts.Path = paths
+
 	fmt.Println("Listing installed packages...")
 
-	packages, err := manager.ListPackages()
+	packages, err := manager.ListPackagesWithOptions(opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to list packages: %v\n", err)
 		os.Exit(1)
@@ -268,15 +377,26 @@ func handleList(manager pip.PipManager, args []string) {
 	}
 
 	fmt.Printf("Found %d installed packages:\n\n", len(packages))
-	fmt.Printf("%-30s %-15s %s\n", "Package", "Version", "Location")
+	if opts.Verbose {
+		fmt.Printf("%-30s %-15s %-10s %s\n", "Package", "Version", "Installer", "Location")
+	} else {
+		fmt.Printf("%-30s %-15s %s\n", "Package", "Version", "Location")
+	}
 	fmt.Printf("%s\n", strings.Repeat("-", 80))
 
 	for _, pkg := range packages {
 		location := pkg.Location
+		if pkg.EditableLocation != "" {
+			location = pkg.EditableLocation
+		}
 		if location == "" {
 			location = "N/A"
 		}
-		fmt.Printf("%-30s %-15s %s\n", pkg.Name, pkg.Version, location)
+		if opts.Verbose {
+			fmt.Printf("%-30s %-15s %-10s %s\n", pkg.Name, pkg.Version, pkg.Installer, location)
+		} else {
+			fmt.Printf("%-30s %-15s %s\n", pkg.Name, pkg.Version, location)
+		}
 	}
 }
 
@@ -342,6 +462,25 @@ func handleFreeze(manager pip.PipManager, args []string) {
 	}
 }
 
+func handleCheck(manager *pip.Manager, args []string) {
+	issues, err := manager.CheckEnvironment()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to check packages: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(issues) == 0 {
+		fmt.Println("No broken requirements found.")
+		return
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	fmt.Fprintf(os.Stderr, "Found %d broken requirements\n", len(issues))
+	os.Exit(1)
+}
+
 func handleVenv(manager pip.PipManager, args []string) {
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: venv subcommand required\n")
@@ -488,24 +627,45 @@ func handleHelp(args []string) {
 	switch command {
 	case "install":
 		fmt.Println("Install Python packages")
-		fmt.Println("Usage: pip-cli install <package1> [package2] ...")
-		fmt.Println("       pip-cli install <package> <version>")
+		fmt.Println("Usage: pip-cli install [-preview] <package1> [package2] ...")
+		fmt.Println("       pip-cli install [-preview] <package> <version>")
+		fmt.Println("Options:")
+		fmt.Println("  -preview            Show what would be installed, upgraded, downgraded or left")
+		fmt.Println("                      unchanged without installing (requires pip 22.2+)")
 		fmt.Println("Examples:")
 		fmt.Println("  pip-cli install requests")
 		fmt.Println("  pip-cli install requests click flask")
 		fmt.Println("  pip-cli install requests '>=2.25.0'")
+		fmt.Println("  pip-cli install -preview requests '>=2.31.0'")
 	case "uninstall":
 		fmt.Println("Uninstall a Python package")
 		fmt.Println("Usage: pip-cli uninstall <package>")
 	case "list":
 		fmt.Println("List installed packages")
-		fmt.Println("Usage: pip-cli list")
+		fmt.Println("Usage: pip-cli list [-not-required] [-user] [-local] [-editable | -exclude-editable]")
+		fmt.Println("                    [-exclude name] [-path dir] [-v]")
+		fmt.Println("Options:")
+		fmt.Println("  -not-required       Only packages that no other package depends on")
+		fmt.Println("  -user               Only packages installed in the user site")
+		fmt.Println("  -local              Skip globally installed packages inside a virtualenv")
+		fmt.Println("  -editable           Only editable installs")
+		fmt.Println("  -exclude-editable   Skip editable installs")
+		fmt.Println("  -exclude name       Leave out a package (repeatable or comma-separated)")
+		fmt.Println("  -path dir           Only packages installed in dir (repeatable)")
+		fmt.Println("  -v                  Show location and installer")
+		fmt.Println("Examples:")
+		fmt.Println("  pip-cli list -not-required")
+		fmt.Println("  pip-cli list -v -exclude pip,setuptools")
 	case "show":
 		fmt.Println("Show package information")
 		fmt.Println("Usage: pip-cli show <package>")
 	case "freeze":
 		fmt.Println("Output installed packages in requirements format")
 		fmt.Println("Usage: pip-cli freeze")
+	case "check":
+		fmt.Println("Verify installed packages have compatible dependencies")
+		fmt.Println("Usage: pip-cli check")
+		fmt.Println("Exits with status 1 when a requirement is missing or has an incompatible version.")
 	case "venv":
 		fmt.Println("Virtual environment operations")
 		fmt.Println("Usage: pip-cli venv <create|activate|deactivate|remove|info> [path]")
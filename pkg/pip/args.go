@@ -0,0 +1,205 @@
+package pip
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// networkCommands lists pip subcommands that talk to a package index
+var networkCommands = map[string]bool{
+	"install":  true,
+	"download": true,
+	"wheel":    true,
+	"index":    true,
+	"search":   true,
+}
+
+// optionAliases maps short pip flags to their long option names
+var optionAliases = map[string]string{
+	"-i": "index-url",
+}
+
+// isNetworkCommand reports whether pip args invoke a command that contacts an index
+func isNetworkCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if networkCommands[args[0]] {
+		return true
+	}
+	if args[0] == "list" {
+		return hasOption(args, "outdated") || hasOption(args, "uptodate")
+	}
+	return false
+}
+
+// applyGlobalOptions adds Config.DefaultIndex, TrustedHosts, CacheDir and
+// ExtraOptions to network-touching pip commands.
+//
+// Settings are applied in this order of precedence, highest first:
+//
+//  1. options already present in args, i.e. given for the individual call
+//     (PackageSpec.Index, PackageSpec.Options and similar per-call settings)
+//  2. Config.ExtraOptions
+//  3. Config.DefaultIndex and Config.CacheDir
+//
+// Config.TrustedHosts are additive: every configured host that is not
+// already trusted by the call is added.
+//
+// In offline mode no index is used: --no-index and a --find-links for each
+// of Config.WheelhouseDirs are added instead of Config.DefaultIndex.
+func (m *Manager) applyGlobalOptions(args []string) []string {
+	if !isNetworkCommand(args) {
+		return args
+	}
+
+	config := m.currentConfig()
+	result := append([]string{}, args...)
+
+	// Config.ExtraOptions, in a stable order
+	keys := make([]string, 0, len(config.ExtraOptions))
+	for key := range config.ExtraOptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := strings.TrimLeft(key, "-")
+		if name == "" || hasOption(result, name) {
+			continue
+		}
+		result = appendOption(result, name, config.ExtraOptions[key])
+	}
+
+	// Dedicated configuration fields
+	if config.Offline {
+		if !hasOption(result, "no-index") {
+			result = appendOption(result, "no-index", "")
+		}
+		links := optionValues(result, "find-links")
+		for _, dir := range config.WheelhouseDirs {
+			if dir != "" && !containsString(links, dir) {
+				result = appendOption(result, "find-links", dir)
+				links = append(links, dir)
+			}
+		}
+	} else if config.DefaultIndex != "" && !hasOption(result, "index-url") {
+		result = appendOption(result, "index-url", config.DefaultIndex)
+	}
+	if config.CacheDir != "" && !hasOption(result, "cache-dir") && !hasOption(result, "no-cache-dir") {
+		result = appendOption(result, "cache-dir", config.CacheDir)
+	}
+
+	trusted := optionValues(result, "trusted-host")
+	for _, host := range config.TrustedHosts {
+		if host != "" && !containsString(trusted, host) {
+			result = appendOption(result, "trusted-host", host)
+			trusted = append(trusted, host)
+		}
+	}
+
+	return result
+}
+
+// mergeSpecOptions merges the index URLs and extra options of specs run in
+// one pip command with the call's own. An index set for the call wins;
+// otherwise the specs must agree on one, as pip takes a single --index-url.
+// optionsType names the options struct in the conflict error.
+func mergeSpecOptions(pkgs []*PackageSpec, index string, options map[string]string, optionsType string) (string, map[string]string, error) {
+	callIndex := index
+	merged := make(map[string]string)
+
+	for _, pkg := range pkgs {
+		if pkg.Index != "" && callIndex == "" {
+			if index != "" && index != pkg.Index {
+				return "", nil, &PipError{
+					Type:    string(ErrorTypeInvalidPackageSpec),
+					Message: fmt.Sprintf("conflicting index URLs %s and %s; set %s.Index", index, pkg.Index, optionsType),
+				}
+			}
+			index = pkg.Index
+		}
+		for key, value := range pkg.Options {
+			merged[key] = value
+		}
+	}
+	for key, value := range options {
+		merged[key] = value
+	}
+	return index, merged, nil
+}
+
+// appendSortedOptions appends extra options to args in a stable order
+func appendSortedOptions(args []string, options map[string]string) []string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		args = appendOption(args, key, options[key])
+	}
+	return args
+}
+
+// appendOption appends "--name" or "--name value" to args
+func appendOption(args []string, name, value string) []string {
+	if value == "" {
+		return append(args, "--"+name)
+	}
+	return append(args, "--"+name, value)
+}
+
+// hasOption reports whether args contain the long option name, in either
+// "--name value" or "--name=value" form, or one of its short aliases
+func hasOption(args []string, name string) bool {
+	for _, arg := range args {
+		if optionName(arg) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// optionValues returns every value given for the long option name
+func optionValues(args []string, name string) []string {
+	var values []string
+	for i, arg := range args {
+		if optionName(arg) != name {
+			continue
+		}
+		if idx := strings.Index(arg, "="); idx >= 0 {
+			values = append(values, arg[idx+1:])
+		} else if i+1 < len(args) {
+			values = append(values, args[i+1])
+		}
+	}
+	return values
+}
+
+// optionName returns the long option name of a flag argument, or "" for positional arguments
+func optionName(arg string) string {
+	if alias, ok := optionAliases[arg]; ok {
+		return alias
+	}
+	if !strings.HasPrefix(arg, "--") {
+		return ""
+	}
+	name := strings.TrimPrefix(arg, "--")
+	if idx := strings.Index(name, "="); idx >= 0 {
+		name = name[:idx]
+	}
+	return name
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package pip
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyGlobalOptions(t *testing.T) {
+	config := &Config{
+		DefaultIndex: "https://mirror.example.com/simple",
+		TrustedHosts: []string{"mirror.example.com", "files.example.com"},
+		CacheDir:     "/var/cache/pip",
+		ExtraOptions: map[string]string{
+			"timeout":   "60",
+			"no-color":  "",
+			"--retries": "5",
+		},
+	}
+	manager := NewManager(config)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "install gets every global",
+			args: []string{"install", "requests"},
+			want: []string{"install", "requests",
+				"--retries", "5", "--no-color", "--timeout", "60",
+				"--index-url", "https://mirror.example.com/simple",
+				"--cache-dir", "/var/cache/pip",
+				"--trusted-host", "mirror.example.com", "--trusted-host", "files.example.com"},
+		},
+		{
+			name: "per-call options win",
+			args: []string{"install", "-r", "req.txt", "-i", "https://other/simple", "--timeout=5", "--no-cache-dir", "--trusted-host", "files.example.com"},
+			want: []string{"install", "-r", "req.txt", "-i", "https://other/simple", "--timeout=5", "--no-cache-dir", "--trusted-host", "files.example.com",
+				"--retries", "5", "--no-color",
+				"--trusted-host", "mirror.example.com"},
+		},
+		{
+			name: "list outdated is network touching",
+			args: []string{"list", "--outdated", "--format=json"},
+			want: []string{"list", "--outdated", "--format=json",
+				"--retries", "5", "--no-color", "--timeout", "60",
+				"--index-url", "https://mirror.example.com/simple",
+				"--cache-dir", "/var/cache/pip",
+				"--trusted-host", "mirror.example.com", "--trusted-host", "files.example.com"},
+		},
+		{
+			name: "local commands are untouched",
+			args: []string{"list", "--format=json"},
+			want: []string{"list", "--format=json"},
+		},
+		{
+			name: "uninstall is untouched",
+			args: []string{"uninstall", "-y", "requests"},
+			want: []string{"uninstall", "-y", "requests"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := manager.applyGlobalOptions(tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyGlobalOptions()\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraOptionsOverrideDefaultIndex(t *testing.T) {
+	manager := NewManager(&Config{
+		DefaultIndex: "https://default/simple",
+		ExtraOptions: map[string]string{"index-url": "https://extra/simple"},
+	})
+
+	got := manager.applyGlobalOptions([]string{"download", "six"})
+	want := []string{"download", "six", "--index-url", "https://extra/simple"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applyGlobalOptions() = %q, want %q", got, want)
+	}
+}
+
+func TestInstallPackageUsesGlobalOptions(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests", "--index-url", "https://pkg/simple", "--trusted-host", "pkg").Respond("", "", 0)
+
+	manager := NewManager(&Config{
+		DefaultIndex: "https://default/simple",
+		TrustedHosts: []string{"pkg"},
+	})
+	manager.SetCommandRunner(runner)
+
+	err := manager.InstallPackage(&PackageSpec{Name: "requests", Index: "https://pkg/simple"})
+	if err != nil {
+		t.Fatalf("InstallPackage() error: %v (calls: %v)", err, runner.Calls())
+	}
+}
+
+func TestOptionHelpers(t *testing.T) {
+	args := []string{"install", "--trusted-host=a", "--trusted-host", "b", "-i", "x"}
+
+	if !hasOption(args, "index-url") {
+		t.Error("hasOption should resolve -i to index-url")
+	}
+	if hasOption(args, "cache-dir") {
+		t.Error("hasOption found an option that is not present")
+	}
+	if got := optionValues(args, "trusted-host"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("optionValues() = %q", got)
+	}
+}
+
+func TestMergeSpecOptions(t *testing.T) {
+	pkgs := []*PackageSpec{
+		{Name: "a", Index: "https://mirror/simple", Options: map[string]string{"pre": "", "timeout": "10"}},
+		{Name: "b", Index: "https://mirror/simple"},
+	}
+
+	index, options, err := mergeSpecOptions(pkgs, "", map[string]string{"timeout": "60"}, "InstallOptions")
+	if err != nil {
+		t.Fatalf("mergeSpecOptions() error: %v", err)
+	}
+	if index != "https://mirror/simple" {
+		t.Errorf("index = %q", index)
+	}
+	if got := strings.Join(appendSortedOptions(nil, options), " "); got != "--pre --timeout 60" {
+		t.Errorf("options = %q, want call options to win", got)
+	}
+
+	pkgs = append(pkgs, &PackageSpec{Name: "c", Index: "https://other/simple"})
+	if _, _, err := mergeSpecOptions(pkgs, "", nil, "WheelOptions"); !IsErrorType(err, ErrorTypeInvalidPackageSpec) || !strings.Contains(err.Error(), "WheelOptions.Index") {
+		t.Errorf("conflicting indexes: error = %v", err)
+	}
+	if index, _, err := mergeSpecOptions(pkgs, "https://call/simple", nil, "WheelOptions"); err != nil || index != "https://call/simple" {
+		t.Errorf("call index: index = %q, error = %v", index, err)
+	}
+}
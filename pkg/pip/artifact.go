@@ -0,0 +1,125 @@
+package pip
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Artifact kinds
+const (
+	ArtifactWheel = "wheel"
+	ArtifactSdist = "sdist"
+)
+
+// sdistExtensions are the source distribution archive formats pip accepts
+var sdistExtensions = []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tgz", ".tbz", ".zip", ".tar"}
+
+// Artifact is a wheel or source distribution file
+type Artifact struct {
+	Path     string `json:"path,omitempty"` // set for files on disk
+	Filename string `json:"filename"`
+	Kind     string `json:"kind"`    // ArtifactWheel or ArtifactSdist
+	Name     string `json:"name"`    // as spelled in the filename
+	Version  string `json:"version"` // as spelled in the filename
+
+	// Wheel tags (PEP 427). Compressed tag sets such as "py2.py3" are
+	// split into their parts.
+	BuildTag     string   `json:"build_tag,omitempty"`
+	PythonTags   []string `json:"python_tags,omitempty"`
+	ABITags      []string `json:"abi_tags,omitempty"`
+	PlatformTags []string `json:"platform_tags,omitempty"`
+
+	// Built is set by BuildWheels for wheels built from a source
+	// distribution or local project, rather than downloaded as wheels.
+	// This includes wheels pip built on an earlier run and took from its
+	// wheel cache.
+	Built bool `json:"built,omitempty"`
+}
+
+// ParseArtifactFilename parses a wheel filename
+// ("name-version[-build]-python-abi-platform.whl") or a source distribution
+// filename ("name-version.tar.gz" and other archive formats)
+func ParseArtifactFilename(filename string) (*Artifact, error) {
+	base := filepath.Base(filename)
+
+	if strings.HasSuffix(base, ".whl") {
+		parts := strings.Split(strings.TrimSuffix(base, ".whl"), "-")
+		if len(parts) != 5 && len(parts) != 6 {
+			return nil, invalidArtifactError(base, "expected 5 or 6 dash-separated parts")
+		}
+		for _, part := range parts {
+			if part == "" {
+				return nil, invalidArtifactError(base, "empty filename part")
+			}
+		}
+
+		artifact := &Artifact{Filename: base, Kind: ArtifactWheel, Name: parts[0], Version: parts[1]}
+		if len(parts) == 6 {
+			if parts[2][0] < '0' || parts[2][0] > '9' {
+				return nil, invalidArtifactError(base, "build tag must start with a digit")
+			}
+			artifact.BuildTag = parts[2]
+		}
+		tags := parts[len(parts)-3:]
+		artifact.PythonTags = strings.Split(tags[0], ".")
+		artifact.ABITags = strings.Split(tags[1], ".")
+		artifact.PlatformTags = strings.Split(tags[2], ".")
+		return artifact, nil
+	}
+
+	for _, ext := range sdistExtensions {
+		if !strings.HasSuffix(base, ext) {
+			continue
+		}
+		name, version := splitNameVersion(strings.TrimSuffix(base, ext))
+		if name == "" || version == "" {
+			return nil, invalidArtifactError(base, "expected name-version")
+		}
+		return &Artifact{Filename: base, Kind: ArtifactSdist, Name: name, Version: version}, nil
+	}
+
+	return nil, invalidArtifactError(base, "not a wheel or source distribution")
+}
+
+// invalidArtifactError reports a filename that cannot be parsed
+func invalidArtifactError(filename, reason string) *PipError {
+	return &PipError{
+		Type:    string(ErrorTypeInvalidPath),
+		Message: fmt.Sprintf("invalid distribution filename %q: %s", filename, reason),
+	}
+}
+
+// IsWheel reports whether the artifact is a wheel
+func (a *Artifact) IsWheel() bool {
+	return a.Kind == ArtifactWheel
+}
+
+// Tags returns every "python-abi-platform" tag the wheel supports, or nil
+// for a source distribution
+func (a *Artifact) Tags() []string {
+	var tags []string
+	for _, python := range a.PythonTags {
+		for _, abi := range a.ABITags {
+			for _, platform := range a.PlatformTags {
+				tags = append(tags, python+"-"+abi+"-"+platform)
+			}
+		}
+	}
+	return tags
+}
+
+// Supports reports whether the wheel can be installed by an interpreter
+// with the given supported tags, as listed by InterpreterInfo.Tags. Source
+// distributions are always supported.
+func (a *Artifact) Supports(supported []string) bool {
+	if !a.IsWheel() {
+		return true
+	}
+	for _, tag := range a.Tags() {
+		if containsString(supported, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package pip
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseArtifactFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     Artifact
+	}{
+		{
+			filename: "numpy-1.26.4-cp311-cp311-manylinux_2_17_aarch64.manylinux2014_aarch64.whl",
+			want: Artifact{
+				Kind: ArtifactWheel, Name: "numpy", Version: "1.26.4",
+				PythonTags: []string{"cp311"}, ABITags: []string{"cp311"},
+				PlatformTags: []string{"manylinux_2_17_aarch64", "manylinux2014_aarch64"},
+			},
+		},
+		{
+			filename: "six-1.16.0-py2.py3-none-any.whl",
+			want: Artifact{
+				Kind: ArtifactWheel, Name: "six", Version: "1.16.0",
+				PythonTags: []string{"py2", "py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"},
+			},
+		},
+		{
+			filename: "/wheelhouse/pkg_name-2.0-1-py3-none-any.whl",
+			want: Artifact{
+				Kind: ArtifactWheel, Name: "pkg_name", Version: "2.0", BuildTag: "1",
+				PythonTags: []string{"py3"}, ABITags: []string{"none"}, PlatformTags: []string{"any"},
+			},
+		},
+		{filename: "requests-2.31.0.tar.gz", want: Artifact{Kind: ArtifactSdist, Name: "requests", Version: "2.31.0"}},
+		{filename: "zope.interface-6.0.zip", want: Artifact{Kind: ArtifactSdist, Name: "zope.interface", Version: "6.0"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseArtifactFilename(tt.filename)
+		if err != nil {
+			t.Errorf("ParseArtifactFilename(%q) error: %v", tt.filename, err)
+			continue
+		}
+		if got.Kind != tt.want.Kind || got.Name != tt.want.Name || got.Version != tt.want.Version || got.BuildTag != tt.want.BuildTag {
+			t.Errorf("ParseArtifactFilename(%q) = %+v", tt.filename, got)
+		}
+		if strings.Join(got.PythonTags, ".") != strings.Join(tt.want.PythonTags, ".") ||
+			strings.Join(got.ABITags, ".") != strings.Join(tt.want.ABITags, ".") ||
+			strings.Join(got.PlatformTags, ".") != strings.Join(tt.want.PlatformTags, ".") {
+			t.Errorf("ParseArtifactFilename(%q) tags = %v %v %v", tt.filename, got.PythonTags, got.ABITags, got.PlatformTags)
+		}
+	}
+
+	for _, filename := range []string{"README.md", "pkg-1.0-py3-any.whl", "pkg-1.0-beta-py3-none-any.whl", "pkg.tar.gz"} {
+		if _, err := ParseArtifactFilename(filename); !IsErrorType(err, ErrorTypeInvalidPath) {
+			t.Errorf("ParseArtifactFilename(%q) error = %v, want invalid_path", filename, err)
+		}
+	}
+}
+
+func TestArtifactTags(t *testing.T) {
+	wheel, err := ParseArtifactFilename("six-1.16.0-py2.py3-none-any.whl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(wheel.Tags(), ","); got != "py2-none-any,py3-none-any" {
+		t.Errorf("Tags() = %s", got)
+	}
+	if !wheel.Supports([]string{"cp311-cp311-linux_x86_64", "py3-none-any"}) {
+		t.Error("universal wheel should be supported")
+	}
+
+	native, _ := ParseArtifactFilename("numpy-1.26.4-cp311-cp311-manylinux2014_aarch64.whl")
+	if native.Supports([]string{"cp311-cp311-manylinux2014_x86_64", "py3-none-any"}) {
+		t.Error("aarch64 wheel should not be supported on x86_64")
+	}
+
+	sdist, _ := ParseArtifactFilename("requests-2.31.0.tar.gz")
+	if sdist.IsWheel() || sdist.Tags() != nil || !sdist.Supports(nil) {
+		t.Errorf("sdist = %+v", sdist)
+	}
+}
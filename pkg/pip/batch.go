@@ -0,0 +1,232 @@
+package pip
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InstallPackages installs several packages with a single pip run so the
+// resolver sees every requirement at once. It returns one InstallResult per
+// spec, in order, attributing the installed version or the failure reported
+// by pip to each package. The returned error is that of the pip run.
+func (m *Manager) InstallPackages(pkgs []*PackageSpec, opts *InstallOptions) ([]*InstallResult, error) {
+	if opts == nil {
+		opts = &InstallOptions{}
+	}
+
+	results := make([]*InstallResult, len(pkgs))
+	for i, pkg := range pkgs {
+		results[i] = &InstallResult{Package: pkg}
+	}
+	if len(pkgs) == 0 {
+		return results, nil
+	}
+
+	// Reject the whole batch if any spec is invalid, before running pip
+	var invalid error
+	for i, pkg := range pkgs {
+		if err := m.validatePackageSpec(pkg); err != nil {
+			results[i].Error = err
+			results[i].Message = err.Error()
+			if invalid == nil {
+				invalid = fmt.Errorf("package %d: %w", i+1, err)
+			}
+		}
+	}
+
+	args, err := buildBatchInstallArgs(pkgs, opts)
+	if err != nil && invalid == nil {
+		invalid = err
+	}
+	if invalid == nil {
+		invalid = m.checkWheelhouse(pkgs, nil)
+	}
+	if invalid != nil {
+		for _, result := range results {
+			if result.Error == nil {
+				result.Error = invalid
+				result.Message = "not installed: " + invalid.Error()
+			}
+		}
+		return results, invalid
+	}
+
+	m.logInfo("Installing %d packages", len(pkgs))
+
+	batch, err := m.runInstallStream(nil, args, opts.Handler)
+	attributeInstallResults(results, batch)
+	return results, err
+}
+
+// buildBatchInstallArgs builds a single pip install command for several
+// packages. Options that pip accepts only once per run are merged from the
+// specs; conflicting index URLs are rejected.
+func buildBatchInstallArgs(pkgs []*PackageSpec, opts *InstallOptions) ([]string, error) {
+	args := []string{"install"}
+
+	upgrade, forceReinstall := opts.Upgrade, opts.ForceReinstall
+	for _, pkg := range pkgs {
+		if pkg.Editable {
+			args = append(args, "--editable")
+		}
+		args = append(args, requirementString(pkg))
+
+		upgrade = upgrade || pkg.Upgrade
+		forceReinstall = forceReinstall || pkg.ForceReinstall
+	}
+
+	index, options, err := mergeSpecOptions(pkgs, opts.Index, opts.Options, "InstallOptions")
+	if err != nil {
+		return nil, err
+	}
+
+	if upgrade {
+		args = append(args, "--upgrade")
+	}
+	if forceReinstall {
+		args = append(args, "--force-reinstall")
+	}
+	if opts.NoDeps {
+		args = append(args, "--no-deps")
+	}
+	if index != "" {
+		args = append(args, "--index-url", index)
+	}
+
+	return appendSortedOptions(args, options), nil
+}
+
+// requirementString formats a spec as a PEP 508 requirement, e.g. "requests[socks]>=2.0"
+func requirementString(pkg *PackageSpec) string {
+	requirement := pkg.Name
+	if len(pkg.Extras) > 0 {
+		requirement += "[" + strings.Join(pkg.Extras, ",") + "]"
+	}
+	return requirement + pkg.Version
+}
+
+// installOutcome is what pip's output says about one package
+type installOutcome struct {
+	version   string
+	satisfied bool      // already installed, nothing to do
+	errorType ErrorType // set when pip blamed the package for a failure
+	line      string
+}
+
+// attributeInstallResults fills per-package results from a batch result
+func attributeInstallResults(results []*InstallResult, batch *InstallResult) {
+	outcomes := parseInstallOutcomes(batch.OutputLines)
+
+	for _, result := range results {
+		result.Duration = batch.Duration
+		result.OutputLines = batch.OutputLines
+
+		outcome := outcomes[normalizePackageName(result.Package.Name)]
+
+		switch {
+		case outcome != nil && outcome.errorType != "":
+			result.Error = &PipError{
+				Type:    string(outcome.errorType),
+				Message: strings.TrimPrefix(outcome.line, "ERROR: "),
+			}
+			result.Message = outcome.line
+
+		case batch.Error != nil:
+			// pip installs nothing when the run fails
+			result.Error = batch.Error
+			result.Message = "not installed: " + batch.Error.Error()
+
+		case outcome != nil && outcome.satisfied:
+			result.Success = true
+			result.Version = outcome.version
+			result.Message = fmt.Sprintf("Requirement already satisfied: %s %s", result.Package.Name, outcome.version)
+
+		case outcome != nil:
+			result.Success = true
+			result.Version = outcome.version
+			result.Message = fmt.Sprintf("Successfully installed %s-%s", result.Package.Name, outcome.version)
+
+		default:
+			result.Success = true
+			result.Message = batch.Message
+		}
+	}
+}
+
+// parseInstallOutcomes reads per-package outcomes from pip install output,
+// keyed by normalized package name
+func parseInstallOutcomes(lines []string) map[string]*installOutcome {
+	outcomes := make(map[string]*installOutcome)
+	set := func(name string, outcome *installOutcome) {
+		if name == "" {
+			return
+		}
+		key := normalizePackageName(name)
+		// A failure is never overwritten by a later success line
+		if existing := outcomes[key]; existing != nil && existing.errorType != "" {
+			return
+		}
+		outcomes[key] = outcome
+	}
+	blame := func(requirement string, errorType ErrorType, line string) {
+		requirement = strings.Trim(strings.TrimSpace(requirement), `'"`)
+		set(requirementNameRegex.FindString(requirement), &installOutcome{errorType: errorType, line: line})
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "Successfully installed "):
+			for _, item := range strings.Fields(strings.TrimPrefix(trimmed, "Successfully installed ")) {
+				name, version := splitNameVersion(item)
+				set(name, &installOutcome{version: version, line: trimmed})
+			}
+
+		case strings.HasPrefix(trimmed, "Requirement already satisfied: "):
+			rest := strings.TrimPrefix(trimmed, "Requirement already satisfied: ")
+			outcome := &installOutcome{satisfied: true, line: trimmed}
+			if idx := strings.LastIndex(rest, "("); idx >= 0 && strings.HasSuffix(rest, ")") {
+				if version := rest[idx+1 : len(rest)-1]; !strings.ContainsAny(version, " ") {
+					outcome.version = version
+				}
+			}
+			set(requirementNameRegex.FindString(rest), outcome)
+
+		case strings.HasPrefix(trimmed, "ERROR: Could not find a version that satisfies the requirement "):
+			blame(strings.TrimPrefix(trimmed, "ERROR: Could not find a version that satisfies the requirement "),
+				ErrorTypePackageNotFound, trimmed)
+
+		case strings.HasPrefix(trimmed, "ERROR: No matching distribution found for "):
+			blame(strings.TrimPrefix(trimmed, "ERROR: No matching distribution found for "),
+				ErrorTypePackageNotFound, trimmed)
+
+		case strings.HasPrefix(trimmed, "ERROR: Invalid requirement: "):
+			blame(strings.TrimPrefix(trimmed, "ERROR: Invalid requirement: "),
+				ErrorTypeInvalidPackageSpec, trimmed)
+
+		case strings.HasPrefix(trimmed, "ERROR: Cannot install ") && strings.Contains(trimmed, " because "):
+			list := strings.TrimPrefix(trimmed, "ERROR: Cannot install ")
+			list = list[:strings.Index(list, " because ")]
+			for _, part := range strings.Split(list, ",") {
+				for _, requirement := range strings.Split(part, " and ") {
+					blame(requirement, ErrorTypeDependencyConflict, trimmed)
+				}
+			}
+
+		case strings.HasPrefix(trimmed, "ERROR: Failed building wheel for "):
+			blame(strings.TrimPrefix(trimmed, "ERROR: Failed building wheel for "), ErrorTypeBuildFailed, trimmed)
+
+		case strings.HasPrefix(trimmed, "ERROR: Could not build wheels for "):
+			list := strings.TrimPrefix(trimmed, "ERROR: Could not build wheels for ")
+			if idx := strings.Index(list, ", which"); idx >= 0 {
+				list = list[:idx]
+			}
+			for _, requirement := range strings.Split(list, ",") {
+				blame(requirement, ErrorTypeBuildFailed, trimmed)
+			}
+		}
+	}
+
+	return outcomes
+}
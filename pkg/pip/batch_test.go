@@ -0,0 +1,136 @@
+package pip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInstallPackagesSingleRun(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests[socks]>=2.0", "click", "django==4.2", "--upgrade", "--no-deps").Respond(
+		"Requirement already satisfied: click in /venv/lib/python3.11/site-packages (8.1.7)\n"+
+			"Collecting requests[socks]>=2.0\n"+
+			"Requirement already satisfied: idna<4,>=2.5 in /venv/lib/python3.11/site-packages (from requests[socks]>=2.0) (3.4)\n"+
+			"Installing collected packages: Django, requests\n"+
+			"Successfully installed Django-4.2 requests-2.31.0\n",
+		"", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	pkgs := []*PackageSpec{
+		{Name: "requests", Version: ">=2.0", Extras: []string{"socks"}},
+		{Name: "click", Upgrade: true},
+		{Name: "django", Version: "==4.2"},
+	}
+	results, err := manager.InstallPackages(pkgs, &InstallOptions{NoDeps: true})
+	if err != nil {
+		t.Fatalf("InstallPackages() error: %v", err)
+	}
+
+	if calls := runner.Calls(); len(calls) != 1 {
+		t.Fatalf("got %d pip runs, want 1", len(calls))
+	}
+	if len(results) != len(pkgs) {
+		t.Fatalf("got %d results, want %d", len(results), len(pkgs))
+	}
+
+	want := []string{"2.31.0", "8.1.7", "4.2"}
+	for i, result := range results {
+		if result.Package != pkgs[i] || !result.Success || result.Error != nil {
+			t.Errorf("result %d = %+v", i, result)
+		}
+		if result.Version != want[i] {
+			t.Errorf("result %d version = %q, want %q", i, result.Version, want[i])
+		}
+	}
+}
+
+func TestInstallPackagesAttributesFailures(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "...").Respond(
+		"Collecting requests\n",
+		"ERROR: Could not find a version that satisfies the requirement nosuchpkg==1.0 (from versions: none)\n"+
+			"ERROR: No matching distribution found for nosuchpkg==1.0\n",
+		1)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	results, err := manager.InstallPackages([]*PackageSpec{
+		{Name: "requests"},
+		{Name: "NoSuchPkg", Version: "==1.0"},
+	}, nil)
+	if err == nil {
+		t.Fatal("InstallPackages() should return the pip error")
+	}
+
+	if results[0].Success || results[0].Error != err {
+		t.Errorf("requests should fail with the batch error, got %+v", results[0])
+	}
+	if results[1].Success || !IsErrorType(results[1].Error, ErrorTypePackageNotFound) {
+		t.Errorf("nosuchpkg error = %v, want %s", results[1].Error, ErrorTypePackageNotFound)
+	}
+}
+
+func TestInstallPackagesValidation(t *testing.T) {
+	runner := NewFakeRunner()
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	results, err := manager.InstallPackages([]*PackageSpec{{Name: "requests"}, {Name: ""}}, nil)
+	if err == nil || results[0].Error == nil || !IsErrorType(results[1].Error, ErrorTypeInvalidPackageSpec) {
+		t.Errorf("invalid spec should reject the batch: err=%v results=%+v %+v", err, results[0], results[1])
+	}
+
+	_, err = manager.InstallPackages([]*PackageSpec{
+		{Name: "a", Index: "https://one.example/simple"},
+		{Name: "b", Index: "https://two.example/simple"},
+	}, nil)
+	if !IsErrorType(err, ErrorTypeInvalidPackageSpec) {
+		t.Errorf("conflicting indexes error = %v", err)
+	}
+
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Errorf("pip should not run for a rejected batch, got %d calls", len(calls))
+	}
+
+	results, err = manager.InstallPackages(nil, nil)
+	if err != nil || len(results) != 0 {
+		t.Errorf("empty batch = %v, %v", results, err)
+	}
+}
+
+func TestParseInstallOutcomes(t *testing.T) {
+	lines := []string{
+		"ERROR: Cannot install flask==2.0 and werkzeug==3.0 because these package versions have conflicting dependencies.",
+		"ERROR: Failed building wheel for lxml",
+		"ERROR: Could not build wheels for lxml, pyyaml, which is required to install pyproject.toml-based projects",
+		"ERROR: Invalid requirement: 'foo=='",
+		"Successfully installed lxml-5.0",
+	}
+
+	outcomes := parseInstallOutcomes(lines)
+
+	got := make(map[string]ErrorType)
+	for name, outcome := range outcomes {
+		got[name] = outcome.errorType
+	}
+	want := map[string]ErrorType{
+		"flask":    ErrorTypeDependencyConflict,
+		"werkzeug": ErrorTypeDependencyConflict,
+		"lxml":     ErrorTypeBuildFailed,
+		"pyyaml":   ErrorTypeBuildFailed,
+		"foo":      ErrorTypeInvalidPackageSpec,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outcomes = %v, want %v", got, want)
+	}
+}
+
+func TestRequirementString(t *testing.T) {
+	pkg := &PackageSpec{Name: "requests", Version: ">=2.0", Extras: []string{"security", "socks"}}
+	if got := requirementString(pkg); got != "requests[security,socks]>=2.0" {
+		t.Errorf("requirementString() = %q", got)
+	}
+}
@@ -0,0 +1,177 @@
+package pip
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PipBinding describes which interpreter a pip executable belongs to
+type PipBinding struct {
+	PythonPath       string `json:"python_path"`
+	PythonExecutable string `json:"python_executable,omitempty"` // sys.executable of PythonPath
+	PythonVersion    string `json:"python_version,omitempty"`    // "X.Y"
+	PipPath          string `json:"pip_path"`
+	PipVersion       string `json:"pip_version,omitempty"`
+	PipLocation      string `json:"pip_location,omitempty"`       // package directory from "pip --version"
+	PipPythonVersion string `json:"pip_python_version,omitempty"` // "X.Y" from "pip --version"
+	ShebangPython    string `json:"shebang_python,omitempty"`     // interpreter named by the pip script
+
+	// Problems lists every mismatch found; empty when pip and Python agree
+	Problems []string `json:"problems,omitempty"`
+}
+
+// OK reports whether pip belongs to the configured interpreter
+func (b *PipBinding) OK() bool {
+	return len(b.Problems) == 0
+}
+
+// pipVersionRegex parses "pip 23.3.1 from /site-packages/pip (python 3.11)"
+var pipVersionRegex = regexp.MustCompile(`^pip (\S+) from (.+) \(python ([0-9]+\.[0-9]+)\)`)
+
+// CheckPipBinding reports whether the pip the manager would otherwise use,
+// Config.PipPath or the first pip on PATH, belongs to the interpreter selected
+// by Config.PythonPath or Config.PythonVersion. It compares the pip script's
+// shebang and the "(python X.Y)" reported by "pip --version" with the
+// interpreter.
+func (m *Manager) CheckPipBinding() (*PipBinding, error) {
+	python, err := m.findPythonExecutable()
+	if err != nil {
+		return nil, err
+	}
+
+	interpreter, err := m.probeInterpreter(python)
+	if err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypePythonNotFound),
+			Message: fmt.Sprintf("failed to run Python interpreter %s: %v", python, err),
+		}
+	}
+
+	binding := &PipBinding{
+		PythonPath:       python,
+		PythonExecutable: interpreter.Executable,
+		PythonVersion:    majorMinor(interpreter.Version),
+		PipPath:          m.currentConfig().PipPath,
+	}
+
+	if binding.PipPath == "" {
+		for _, name := range []string{"pip", "pip3"} {
+			if path, err := m.lookPath(name); err == nil {
+				binding.PipPath = path
+				break
+			}
+		}
+	}
+	if binding.PipPath == "" {
+		// Only "<python> -m pip" is available, which is bound by construction
+		binding.PipPath = python + pipModuleSuffix
+	}
+
+	// Interpreter named by the pip launcher
+	if prefix := strings.TrimSuffix(binding.PipPath, pipModuleSuffix); prefix != binding.PipPath {
+		binding.ShebangPython = prefix
+	} else {
+		binding.ShebangPython = m.readShebang(binding.PipPath)
+	}
+	if binding.ShebangPython != "" && !sameExecutable(binding.ShebangPython, python, interpreter.Executable) {
+		binding.Problems = append(binding.Problems, fmt.Sprintf(
+			"pip at %s runs %s, not the configured Python %s", binding.PipPath, binding.ShebangPython, python))
+	}
+
+	// Interpreter reported by pip itself
+	cmd := m.pipCommand(binding.PipPath, "--version")
+	result, err := m.runCommand(cmd)
+	if err != nil {
+		binding.Problems = append(binding.Problems, fmt.Sprintf("%s failed: %v", cmd, err))
+		return binding, nil
+	}
+
+	if match := pipVersionRegex.FindStringSubmatch(strings.TrimSpace(result.Stdout)); match != nil {
+		binding.PipVersion = match[1]
+		binding.PipLocation = match[2]
+		binding.PipPythonVersion = match[3]
+		if binding.PythonVersion != "" && binding.PipPythonVersion != binding.PythonVersion {
+			binding.Problems = append(binding.Problems, fmt.Sprintf(
+				"pip at %s belongs to Python %s, but the configured Python %s is %s",
+				binding.PipPath, binding.PipPythonVersion, python, binding.PythonVersion))
+		}
+	}
+
+	for _, problem := range binding.Problems {
+		m.logWarn("pip/python mismatch: %s", problem)
+	}
+
+	return binding, nil
+}
+
+// readShebang returns the interpreter named on the first line of a script,
+// resolving "#!/usr/bin/env python3" through PATH. It returns "" for binaries.
+func (m *Manager) readShebang(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && line == "" {
+		return ""
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	if filepath.Base(fields[0]) == "env" {
+		// Skip env options such as "-S"
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "-") {
+				continue
+			}
+			if resolved, err := m.lookPath(field); err == nil {
+				return resolved
+			}
+			return field
+		}
+		return ""
+	}
+	return fields[0]
+}
+
+// sameExecutable reports whether path refers to one of the given executables
+func sameExecutable(path string, candidates ...string) bool {
+	resolved := resolvePath(path)
+	for _, candidate := range candidates {
+		if candidate != "" && resolvePath(candidate) == resolved {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath returns the absolute path with symlinks resolved where possible
+func resolvePath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.Clean(path)
+}
+
+// majorMinor returns "X.Y" from a version such as "3.11.4"
+func majorMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,124 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeBindingEnv creates a python executable and a pip script whose shebang
+// names pipPython, and scripts the probe output of python
+func fakeBindingEnv(t *testing.T, pythonVersion string) (dir, python string, runner *FakeRunner) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("pip binding test uses Unix shebangs")
+	}
+
+	dir = t.TempDir()
+	python = filepath.Join(dir, "python3")
+	if err := os.WriteFile(python, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner = NewFakeRunner()
+	runner.On(python, "-c", "*").Respond(fmt.Sprintf(
+		`{"version": %q, "implementation": "CPython", "architecture": "x86_64", "executable": %q}`,
+		pythonVersion, python), "", 0)
+	return dir, python, runner
+}
+
+func writePipScript(t *testing.T, dir, shebang string) string {
+	t.Helper()
+
+	pip := filepath.Join(dir, "pip")
+	if err := os.WriteFile(pip, []byte(shebang+"\nimport sys\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return pip
+}
+
+func TestCheckPipBindingDefault(t *testing.T) {
+	_, python, runner := fakeBindingEnv(t, "3.11.4")
+	runner.Paths = map[string]string{}
+	runner.On(python, "-m", "pip", "--version").Respond(
+		"pip 23.3.1 from /lib/python3.11/site-packages/pip (python 3.11)\n", "", 0)
+
+	manager := NewManager(&Config{PythonPath: python})
+	manager.SetCommandRunner(runner)
+
+	binding, err := manager.CheckPipBinding()
+	if err != nil {
+		t.Fatalf("CheckPipBinding() error: %v", err)
+	}
+	if !binding.OK() {
+		t.Errorf("problems = %v", binding.Problems)
+	}
+	if binding.PipPath != python+" -m pip" || binding.PythonVersion != "3.11" ||
+		binding.PipVersion != "23.3.1" || binding.PipPythonVersion != "3.11" {
+		t.Errorf("binding = %+v", binding)
+	}
+
+	// Packages are managed through the interpreter itself
+	pipPath, err := manager.findPipExecutable()
+	if err != nil || pipPath != python+" -m pip" {
+		t.Errorf("findPipExecutable() = %q, %v", pipPath, err)
+	}
+}
+
+func TestCheckPipBindingShebangMismatch(t *testing.T) {
+	dir, python, runner := fakeBindingEnv(t, "3.11.4")
+	pip := writePipScript(t, dir, "#!/usr/bin/env python3.9")
+	runner.Paths = map[string]string{"pip": pip, "python3.9": "/usr/bin/python3.9"}
+	runner.On(pip, "--version").Respond(
+		"pip 21.0 from /usr/lib/python3.9/site-packages/pip (python 3.9)\n", "", 0)
+
+	manager := NewManager(&Config{PythonPath: python})
+	manager.SetCommandRunner(runner)
+
+	binding, err := manager.CheckPipBinding()
+	if err != nil {
+		t.Fatalf("CheckPipBinding() error: %v", err)
+	}
+	if binding.PipPath != pip || binding.ShebangPython != "/usr/bin/python3.9" {
+		t.Errorf("binding = %+v", binding)
+	}
+	if len(binding.Problems) != 2 {
+		t.Fatalf("problems = %v, want shebang and version mismatches", binding.Problems)
+	}
+	if !strings.Contains(binding.Problems[0], "/usr/bin/python3.9") ||
+		!strings.Contains(binding.Problems[1], "Python 3.9") {
+		t.Errorf("problems = %v", binding.Problems)
+	}
+}
+
+func TestCheckPipBindingVersionMismatch(t *testing.T) {
+	dir, python, runner := fakeBindingEnv(t, "3.12.1")
+	pip := writePipScript(t, dir, "#!"+python)
+	runner.On(pip, "--version").Respond(
+		"pip 23.0 from /usr/lib/python3.11/site-packages/pip (python 3.11)\n", "", 0)
+
+	manager := NewManager(&Config{PythonPath: python, PipPath: pip})
+	manager.SetCommandRunner(runner)
+
+	binding, err := manager.CheckPipBinding()
+	if err != nil {
+		t.Fatalf("CheckPipBinding() error: %v", err)
+	}
+	if binding.ShebangPython != python {
+		t.Errorf("ShebangPython = %q, want %q", binding.ShebangPython, python)
+	}
+	if binding.OK() || len(binding.Problems) != 1 || !strings.Contains(binding.Problems[0], "3.11") {
+		t.Errorf("problems = %v, want a single version mismatch", binding.Problems)
+	}
+}
+
+func TestCommandErrorTypePipModuleMissing(t *testing.T) {
+	output := "/usr/bin/python3: No module named pip"
+	if got := commandErrorType(output, fmt.Errorf("exit status 1")); got != ErrorTypePipNotInstalled {
+		t.Errorf("commandErrorType() = %q, want %q", got, ErrorTypePipNotInstalled)
+	}
+}
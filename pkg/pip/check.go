@@ -0,0 +1,109 @@
+package pip
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DependencyIssueKind classifies a problem reported by pip check
+type DependencyIssueKind string
+
+const (
+	IssueMissing     DependencyIssueKind = "missing"     // a requirement is not installed
+	IssueConflict    DependencyIssueKind = "conflict"    // the installed version does not satisfy a requirement
+	IssueUnsupported DependencyIssueKind = "unsupported" // an installed wheel does not support this platform
+)
+
+// DependencyIssue is a broken requirement in the environment
+type DependencyIssue struct {
+	Kind    DependencyIssueKind `json:"kind"`
+	Package string              `json:"package"`           // the requiring package
+	Version string              `json:"version,omitempty"` // its installed version
+
+	// Requirement is what Package requires, e.g. "urllib3<3,>=1.21.1", and
+	// Dependency its project name. Both are empty for IssueUnsupported.
+	Requirement string `json:"requirement,omitempty"`
+	Dependency  string `json:"dependency,omitempty"`
+
+	// InstalledVersion is the installed version of Dependency, empty when
+	// it is missing
+	InstalledVersion string `json:"installed_version,omitempty"`
+
+	Message string `json:"message"` // pip's line
+}
+
+// String returns pip's description of the issue
+func (i *DependencyIssue) String() string {
+	return i.Message
+}
+
+var (
+	// "requests 2.31.0 requires idna, which is not installed."
+	checkMissingRegex = regexp.MustCompile(`^(\S+) (\S+) requires (.+), which is not installed\.$`)
+
+	// "botocore 1.29.0 has requirement urllib3<1.27,>=1.25.4, but you have urllib3 2.0.7."
+	checkConflictRegex = regexp.MustCompile(`^(\S+) (\S+) has requirement (.+), but you have (\S+) (\S+)\.$`)
+
+	// "torch 2.1.0 is not supported on this platform"
+	checkUnsupportedRegex = regexp.MustCompile(`^(\S+) (\S+) is not supported on this platform\.?$`)
+)
+
+// CheckEnvironment runs "pip check" and returns the broken requirements it
+// reports, or none when the environment is consistent. An error is
+// returned only when pip fails without reporting issues.
+func (m *Manager) CheckEnvironment() ([]*DependencyIssue, error) {
+	m.logInfo("Checking installed packages for broken requirements")
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return nil, ErrPipNotInstalled
+	}
+
+	output, err := m.executePipCommandWithOutput(pipPath, []string{"check"})
+	issues := parseCheckOutput(output)
+
+	// pip check exits with status 1 when it finds issues
+	if err != nil && len(issues) == 0 {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// parseCheckOutput parses the issues in pip check output
+func parseCheckOutput(output string) []*DependencyIssue {
+	var issues []*DependencyIssue
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := checkMissingRegex.FindStringSubmatch(line); match != nil {
+			issues = append(issues, &DependencyIssue{
+				Kind:        IssueMissing,
+				Package:     match[1],
+				Version:     match[2],
+				Requirement: match[3],
+				Dependency:  requirementNameRegex.FindString(match[3]),
+				Message:     line,
+			})
+		} else if match := checkConflictRegex.FindStringSubmatch(line); match != nil {
+			issues = append(issues, &DependencyIssue{
+				Kind:             IssueConflict,
+				Package:          match[1],
+				Version:          match[2],
+				Requirement:      match[3],
+				Dependency:       match[4],
+				InstalledVersion: match[5],
+				Message:          line,
+			})
+		} else if match := checkUnsupportedRegex.FindStringSubmatch(line); match != nil {
+			issues = append(issues, &DependencyIssue{
+				Kind:    IssueUnsupported,
+				Package: match[1],
+				Version: match[2],
+				Message: line,
+			})
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,66 @@
+package pip
+
+import (
+	"testing"
+)
+
+func TestCheckEnvironment(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "check").Respond(
+		"requests 2.31.0 requires idna<4,>=2.5, which is not installed.\n"+
+			"botocore 1.29.0 has requirement urllib3<1.27,>=1.25.4, but you have urllib3 2.0.7.\n"+
+			"torch 2.1.0 is not supported on this platform\n",
+		"", 1)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	issues, err := manager.CheckEnvironment()
+	if err != nil {
+		t.Fatalf("CheckEnvironment() error: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("got %d issues, want 3: %+v", len(issues), issues)
+	}
+
+	missing := issues[0]
+	if missing.Kind != IssueMissing || missing.Package != "requests" || missing.Version != "2.31.0" ||
+		missing.Requirement != "idna<4,>=2.5" || missing.Dependency != "idna" || missing.InstalledVersion != "" {
+		t.Errorf("missing = %+v", missing)
+	}
+
+	conflict := issues[1]
+	if conflict.Kind != IssueConflict || conflict.Package != "botocore" || conflict.Requirement != "urllib3<1.27,>=1.25.4" ||
+		conflict.Dependency != "urllib3" || conflict.InstalledVersion != "2.0.7" {
+		t.Errorf("conflict = %+v", conflict)
+	}
+
+	if unsupported := issues[2]; unsupported.Kind != IssueUnsupported || unsupported.Package != "torch" || unsupported.Version != "2.1.0" {
+		t.Errorf("unsupported = %+v", unsupported)
+	}
+}
+
+func TestCheckEnvironmentClean(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "check").Respond("No broken requirements found.\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	issues, err := manager.CheckEnvironment()
+	if err != nil || len(issues) != 0 {
+		t.Errorf("CheckEnvironment() = %v, %v; want no issues", issues, err)
+	}
+}
+
+func TestCheckEnvironmentFailure(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "check").Respond("", "/usr/bin/python: No module named pip\n", 1)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.CheckEnvironment(); !IsErrorType(err, ErrorTypePipNotInstalled) {
+		t.Errorf("error = %v, want pip_not_installed", err)
+	}
+}
@@ -0,0 +1,230 @@
+package pip
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// wheelCacheRegex matches a wheel in pip's wheel cache, which holds wheels
+// pip built on earlier runs under "wheels/" and the sha224 of their link
+// split 2/2/2/50, e.g.
+// "~/.cache/pip/wheels/b6/e8/fe/f688...981/demo-1.0-py3-none-any.whl"
+var wheelCacheRegex = regexp.MustCompile(`[/\\]wheels[/\\][0-9a-f]{2}[/\\][0-9a-f]{2}[/\\][0-9a-f]{2}[/\\][0-9a-f]{50}[/\\][^/\\]+\.whl$`)
+
+// DownloadOptions controls DownloadPackages
+type DownloadOptions struct {
+	Dest         string   `json:"dest"`                   // directory the files are written to, created if missing
+	Requirements []string `json:"requirements,omitempty"` // requirement files to download (-r)
+
+	// Target environment, for downloading wheels for another platform or
+	// Python. Setting any of these requires NoDeps, or OnlyBinary of ":all:"
+	// with no NoBinary, as pip cannot run builds for a foreign target.
+	Platforms      []string `json:"platforms,omitempty"`      // e.g. "manylinux2014_aarch64"
+	PythonVersion  string   `json:"python_version,omitempty"` // e.g. "3.11"
+	Implementation string   `json:"implementation,omitempty"` // "cp", "pp", "ip", "jy" or "py"
+	ABIs           []string `json:"abis,omitempty"`           // e.g. "cp311", "abi3", "none"
+
+	OnlyBinary []string          `json:"only_binary,omitempty"` // package names, ":all:" or ":none:"
+	NoBinary   []string          `json:"no_binary,omitempty"`   // package names, ":all:" or ":none:"
+	NoDeps     bool              `json:"no_deps,omitempty"`
+	Index      string            `json:"index,omitempty"`   // custom index URL
+	Options    map[string]string `json:"options,omitempty"` // additional pip options
+	Handler    OutputHandler     `json:"-"`                 // receives output lines as they are produced
+}
+
+// DownloadPackages downloads packages and their dependencies into
+// opts.Dest with "pip download" and returns the files it saved or found
+// already downloaded, with their filenames parsed.
+func (m *Manager) DownloadPackages(pkgs []*PackageSpec, opts *DownloadOptions) ([]*Artifact, error) {
+	if opts == nil || opts.Dest == "" {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPath),
+			Message: "download destination cannot be empty",
+		}
+	}
+	if len(pkgs) == 0 && len(opts.Requirements) == 0 {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPackageSpec),
+			Message: "no packages to download",
+		}
+	}
+	for _, pkg := range pkgs {
+		if err := m.validatePackageSpec(pkg); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range opts.Requirements {
+		if err := m.validateRequirementsFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	dest, err := filepath.Abs(opts.Dest)
+	if err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPath),
+			Message: fmt.Sprintf("invalid download destination: %v", err),
+		}
+	}
+
+	args, err := buildDownloadArgs(dest, pkgs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logInfo("Downloading %d packages to %s", len(pkgs)+len(opts.Requirements), dest)
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return nil, ErrPipNotInstalled
+	}
+
+	if err := m.mkdirAll(dest, 0755); err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypePermissionDenied),
+			Message: fmt.Sprintf("failed to create download destination: %v", err),
+		}
+	}
+	if m.planPipCommand(pipPath, args, "download packages") {
+		return nil, nil
+	}
+
+	var lines []string
+	collect := func(line OutputLine) {
+		lines = append(lines, line.Text)
+		if opts.Handler != nil {
+			opts.Handler(line)
+		}
+	}
+
+	_, err = m.executePipCommandStreaming(pipPath, args, collect, func() { lines = nil })
+	return savedArtifacts(dest, lines), err
+}
+
+// buildDownloadArgs builds the pip download command writing to dest
+func buildDownloadArgs(dest string, pkgs []*PackageSpec, opts *DownloadOptions) ([]string, error) {
+	targeted := len(opts.Platforms) > 0 || opts.PythonVersion != "" || opts.Implementation != "" || len(opts.ABIs) > 0
+	binaryOnly := containsString(opts.OnlyBinary, ":all:") &&
+		(len(opts.NoBinary) == 0 || (len(opts.NoBinary) == 1 && opts.NoBinary[0] == ":none:"))
+	if targeted && !opts.NoDeps && !binaryOnly {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidConfig),
+			Message: "downloading for another platform, Python version, implementation or ABI requires NoDeps or OnlyBinary \":all:\"",
+		}
+	}
+
+	args := []string{"download", "--dest", dest}
+
+	for _, pkg := range pkgs {
+		args = append(args, requirementString(pkg))
+	}
+	index, options, err := mergeSpecOptions(pkgs, opts.Index, opts.Options, "DownloadOptions")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range opts.Requirements {
+		args = append(args, "-r", path)
+	}
+	for _, platform := range opts.Platforms {
+		args = append(args, "--platform", platform)
+	}
+	if opts.PythonVersion != "" {
+		args = append(args, "--python-version", opts.PythonVersion)
+	}
+	if opts.Implementation != "" {
+		args = append(args, "--implementation", opts.Implementation)
+	}
+	for _, abi := range opts.ABIs {
+		args = append(args, "--abi", abi)
+	}
+	if len(opts.OnlyBinary) > 0 {
+		args = append(args, "--only-binary", strings.Join(opts.OnlyBinary, ","))
+	}
+	if len(opts.NoBinary) > 0 {
+		args = append(args, "--no-binary", strings.Join(opts.NoBinary, ","))
+	}
+	if opts.NoDeps {
+		args = append(args, "--no-deps")
+	}
+	if index != "" {
+		args = append(args, "--index-url", index)
+	}
+
+	return appendSortedOptions(args, options), nil
+}
+
+// savedArtifacts returns the files pip reports as saved, already present or
+// built in dest, in output order and without duplicates. pip prints their
+// paths relative to its working directory, so only the base names are used.
+// Wheels built now or taken from pip's wheel cache are marked as built.
+func savedArtifacts(dest string, lines []string) []*Artifact {
+	var artifacts []*Artifact
+	seen := make(map[string]bool)
+	cached := make(map[string]bool)
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		var path string
+		built := false
+		switch {
+		case strings.HasPrefix(trimmed, "Using cached "):
+			// A wheel from the wheel cache is logged without a size,
+			// "Using cached demo-1.0-py3-none-any.whl", unlike a download
+			// from the HTTP cache, "Using cached six-1.16.0-py2.py3-none-any.whl (11 kB)"
+			if file := strings.TrimPrefix(trimmed, "Using cached "); strings.HasSuffix(file, ".whl") {
+				cached[artifactFilename(file)] = true
+			}
+			continue
+		case strings.HasPrefix(trimmed, "Processing "):
+			// Older pip logs wheel cache hits by path:
+			// "Processing /root/.cache/pip/wheels/.../demo-1.0-py3-none-any.whl"
+			cache := strings.TrimPrefix(trimmed, "Processing ")
+			if idx := strings.Index(cache, " (from "); idx >= 0 {
+				cache = cache[:idx]
+			}
+			if wheelCacheRegex.MatchString(cache) {
+				cached[artifactFilename(cache)] = true
+			}
+			continue
+		case strings.HasPrefix(trimmed, "Saved "):
+			path = strings.TrimPrefix(trimmed, "Saved ")
+		case strings.HasPrefix(trimmed, "File was already downloaded "):
+			path = strings.TrimPrefix(trimmed, "File was already downloaded ")
+		case strings.HasPrefix(trimmed, "Created wheel for "):
+			// "Created wheel for demo: filename=demo-1.0-py3-none-any.whl size=1024 sha256=..."
+			for _, field := range strings.Fields(trimmed) {
+				if strings.HasPrefix(field, "filename=") {
+					path, built = strings.TrimPrefix(field, "filename="), true
+				}
+			}
+		}
+		if path == "" {
+			continue
+		}
+
+		filename := artifactFilename(path)
+		if seen[filename] {
+			continue
+		}
+		seen[filename] = true
+
+		artifact, err := ParseArtifactFilename(filename)
+		if err != nil {
+			artifact = &Artifact{Filename: filename}
+		}
+		artifact.Path = filepath.Join(dest, filename)
+		artifact.Built = built || cached[filename]
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts
+}
+
+// artifactFilename returns the base name of a path printed by pip, which
+// may use either separator
+func artifactFilename(path string) string {
+	return filepath.Base(filepath.FromSlash(strings.ReplaceAll(path, `\`, "/")))
+}
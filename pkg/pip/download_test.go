@@ -0,0 +1,134 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownloadPackagesForPlatform(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "wheelhouse")
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "download", "--dest", dest, "numpy==1.26.4", "six",
+		"--platform", "manylinux2014_aarch64", "--python-version", "3.11", "--implementation", "cp",
+		"--abi", "cp311", "--only-binary", ":all:").Respond(
+		"Collecting numpy==1.26.4\n"+
+			"  Downloading numpy-1.26.4-cp311-cp311-manylinux_2_17_aarch64.manylinux2014_aarch64.whl (14.2 MB)\n"+
+			"Saved ./wheelhouse/numpy-1.26.4-cp311-cp311-manylinux_2_17_aarch64.manylinux2014_aarch64.whl\n"+
+			"Collecting six\n"+
+			"  File was already downloaded "+dest+"/six-1.16.0-py2.py3-none-any.whl\n"+
+			"Successfully downloaded numpy six\n",
+		"", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	artifacts, err := manager.DownloadPackages([]*PackageSpec{
+		{Name: "numpy", Version: "==1.26.4"},
+		{Name: "six"},
+	}, &DownloadOptions{
+		Dest:           dest,
+		Platforms:      []string{"manylinux2014_aarch64"},
+		PythonVersion:  "3.11",
+		Implementation: "cp",
+		ABIs:           []string{"cp311"},
+		OnlyBinary:     []string{":all:"},
+	})
+	if err != nil {
+		t.Fatalf("DownloadPackages() error: %v", err)
+	}
+
+	if info, err := os.Stat(dest); err != nil || !info.IsDir() {
+		t.Errorf("destination was not created: %v", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("got %d artifacts, want 2: %+v", len(artifacts), artifacts)
+	}
+
+	numpy := artifacts[0]
+	if numpy.Name != "numpy" || numpy.Version != "1.26.4" || !numpy.IsWheel() {
+		t.Errorf("numpy = %+v", numpy)
+	}
+	if numpy.Path != filepath.Join(dest, numpy.Filename) {
+		t.Errorf("numpy path = %s", numpy.Path)
+	}
+	if strings.Join(numpy.PlatformTags, ",") != "manylinux_2_17_aarch64,manylinux2014_aarch64" {
+		t.Errorf("numpy platforms = %v", numpy.PlatformTags)
+	}
+	if artifacts[1].Filename != "six-1.16.0-py2.py3-none-any.whl" {
+		t.Errorf("six = %+v", artifacts[1])
+	}
+}
+
+func TestDownloadPackagesValidation(t *testing.T) {
+	manager := NewManager(nil)
+	manager.SetCommandRunner(NewFakeRunner())
+
+	pkgs := []*PackageSpec{{Name: "numpy"}}
+	tests := []struct {
+		name string
+		pkgs []*PackageSpec
+		opts *DownloadOptions
+		want ErrorType
+	}{
+		{"no destination", pkgs, nil, ErrorTypeInvalidPath},
+		{"no packages", nil, &DownloadOptions{Dest: "out"}, ErrorTypeInvalidPackageSpec},
+		{"foreign platform with sdists", pkgs, &DownloadOptions{Dest: "out", Platforms: []string{"win_amd64"}}, ErrorTypeInvalidConfig},
+		{"foreign python with no-binary", pkgs, &DownloadOptions{
+			Dest: "out", PythonVersion: "3.8", OnlyBinary: []string{":all:"}, NoBinary: []string{"numpy"},
+		}, ErrorTypeInvalidConfig},
+		{"conflicting indexes", []*PackageSpec{
+			{Name: "a", Index: "https://one.example.com/simple"},
+			{Name: "b", Index: "https://two.example.com/simple"},
+		}, &DownloadOptions{Dest: "out"}, ErrorTypeInvalidPackageSpec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := manager.DownloadPackages(tt.pkgs, tt.opts); !IsErrorType(err, tt.want) {
+				t.Errorf("error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadPackagesNoDepsArgs(t *testing.T) {
+	args, err := buildDownloadArgs("/out", []*PackageSpec{{Name: "cryptography", Options: map[string]string{"pre": ""}}}, &DownloadOptions{
+		Platforms: []string{"macosx_11_0_arm64"},
+		NoDeps:    true,
+		NoBinary:  []string{":none:"},
+		Index:     "https://mirror.example.com/simple",
+	})
+	if err != nil {
+		t.Fatalf("buildDownloadArgs() error: %v", err)
+	}
+
+	want := "download --dest /out cryptography --platform macosx_11_0_arm64 --no-binary :none: --no-deps --index-url https://mirror.example.com/simple --pre"
+	if got := strings.Join(args, " "); got != want {
+		t.Errorf("args = %s\nwant   %s", got, want)
+	}
+}
+
+func TestDownloadPackagesDryRun(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "wheelhouse")
+
+	runner := NewFakeRunner()
+	manager := NewManager(&Config{DryRun: true})
+	manager.SetCommandRunner(runner)
+
+	artifacts, err := manager.DownloadPackages([]*PackageSpec{{Name: "six"}}, &DownloadOptions{Dest: dest})
+	if err != nil || artifacts != nil {
+		t.Fatalf("DownloadPackages() = %v, %v", artifacts, err)
+	}
+	if len(runner.Calls()) != 0 {
+		t.Error("dry run should not run pip")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("dry run should not create the destination")
+	}
+	if commands := manager.Plan().Commands(); len(commands) != 1 {
+		t.Errorf("planned %d commands, want 1", len(commands))
+	}
+}
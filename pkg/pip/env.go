@@ -0,0 +1,128 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// EnvironmentPolicy controls which parent environment variables subprocesses inherit
+type EnvironmentPolicy string
+
+const (
+	// EnvInherit passes the parent environment through. This is the default.
+	EnvInherit EnvironmentPolicy = "inherit"
+	// EnvClean starts subprocesses with only Config.Environment
+	EnvClean EnvironmentPolicy = "clean"
+	// EnvAllowlist passes only the parent variables named in Config.EnvironmentAllowlist
+	EnvAllowlist EnvironmentPolicy = "allowlist"
+)
+
+// strippedEnvPrefixes are parent variables removed under every policy so that
+// pip behaves the same regardless of the caller's shell configuration
+var strippedEnvPrefixes = []string{"PIP_", "PYTHON"}
+
+// buildEnvironment returns the subprocess environment for the configured policy.
+//
+// The parent environment is filtered by Config.EnvironmentPolicy, PIP_* and
+// PYTHON* variables are removed from it unless explicitly allowlisted, and
+// Config.Environment is layered on top so it always wins. An unknown policy
+// is an invalid_config error rather than a fallback to inheriting.
+func (m *Manager) buildEnvironment() ([]string, error) {
+	return buildEnvironment(os.Environ(), m.currentConfig())
+}
+
+// buildEnvironment applies the policy in config to the given parent environment
+func buildEnvironment(parent []string, config *Config) ([]string, error) {
+	env := make(map[string]string)
+	names := make(map[string]string) // normalized key -> original name
+
+	set := func(name, value string) {
+		key := envKey(name)
+		env[key] = value
+		names[key] = name
+	}
+
+	policy := config.EnvironmentPolicy
+	switch policy {
+	case "":
+		policy = EnvInherit
+	case EnvInherit, EnvClean, EnvAllowlist:
+	default:
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidConfig),
+			Message: fmt.Sprintf("unknown environment policy %q (want %q, %q or %q)", policy, EnvInherit, EnvClean, EnvAllowlist),
+		}
+	}
+
+	if policy != EnvClean {
+		for _, entry := range parent {
+			idx := strings.Index(entry, "=")
+			if idx <= 0 {
+				continue
+			}
+			name, value := entry[:idx], entry[idx+1:]
+
+			allowlisted := envAllowlisted(name, config.EnvironmentAllowlist)
+			if policy == EnvAllowlist && !allowlisted {
+				continue
+			}
+			if isStrippedEnv(name) && !allowlisted {
+				continue
+			}
+			set(name, value)
+		}
+	}
+
+	for name, value := range config.Environment {
+		set(name, value)
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, names[key]+"="+env[key])
+	}
+	return result, nil
+}
+
+// isStrippedEnv reports whether a parent variable is removed for reproducibility
+func isStrippedEnv(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, prefix := range strippedEnvPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// envAllowlisted reports whether name matches an allowlist entry.
+// Entries are exact names or glob patterns such as "LC_*".
+func envAllowlisted(name string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		if envKey(pattern) == envKey(name) {
+			return true
+		}
+		if matched, err := filepath.Match(envKey(pattern), envKey(name)); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// envKey normalizes a variable name; names are case-insensitive on Windows
+func envKey(name string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(name)
+	}
+	return name
+}
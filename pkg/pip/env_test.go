@@ -0,0 +1,123 @@
+package pip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildEnvironment(t *testing.T) {
+	parent := []string{
+		"HOME=/home/ci",
+		"PATH=/usr/bin",
+		"HTTPS_PROXY=http://proxy:3128",
+		"SSL_CERT_FILE=/etc/ssl/ca.pem",
+		"PIP_INDEX_URL=https://stale/simple",
+		"PYTHONPATH=/tmp/junk",
+		"LC_ALL=C",
+		"MALFORMED",
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   []string
+	}{
+		{
+			name:   "inherit strips pip and python variables",
+			config: &Config{},
+			want: []string{
+				"HOME=/home/ci",
+				"HTTPS_PROXY=http://proxy:3128",
+				"LC_ALL=C",
+				"PATH=/usr/bin",
+				"SSL_CERT_FILE=/etc/ssl/ca.pem",
+			},
+		},
+		{
+			name: "inherit keeps parent when overrides are set",
+			config: &Config{
+				EnvironmentPolicy: EnvInherit,
+				Environment:       map[string]string{"PATH": "/venv/bin:/usr/bin", "PIP_NO_COLOR": "1"},
+			},
+			want: []string{
+				"HOME=/home/ci",
+				"HTTPS_PROXY=http://proxy:3128",
+				"LC_ALL=C",
+				"PATH=/venv/bin:/usr/bin",
+				"PIP_NO_COLOR=1",
+				"SSL_CERT_FILE=/etc/ssl/ca.pem",
+			},
+		},
+		{
+			name: "clean uses only overrides",
+			config: &Config{
+				EnvironmentPolicy: EnvClean,
+				Environment:       map[string]string{"HOME": "/tmp/home"},
+			},
+			want: []string{"HOME=/tmp/home"},
+		},
+		{
+			name: "allowlist with globs and explicit pip variable",
+			config: &Config{
+				EnvironmentPolicy:    EnvAllowlist,
+				EnvironmentAllowlist: []string{"HOME", "LC_*", "PIP_INDEX_URL"},
+			},
+			want: []string{
+				"HOME=/home/ci",
+				"LC_ALL=C",
+				"PIP_INDEX_URL=https://stale/simple",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildEnvironment(parent, tt.config)
+			if err != nil {
+				t.Fatalf("buildEnvironment() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildEnvironment()\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCommandUsesEnvironmentPolicy(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("pip", "...").Respond("", "", 0)
+
+	manager := NewManager(&Config{
+		EnvironmentPolicy: EnvClean,
+		Environment:       map[string]string{"FOO": "bar"},
+	})
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.executePipCommandWithOutput("pip", []string{"list"}); err != nil {
+		t.Fatalf("executePipCommandWithOutput() error: %v", err)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0].Env, []string{"FOO=bar"}) {
+		t.Errorf("command env = %q, want [FOO=bar]", calls[0].Env)
+	}
+}
+
+func TestUnknownEnvironmentPolicy(t *testing.T) {
+	if _, err := buildEnvironment([]string{"SECRET=x"}, &Config{EnvironmentPolicy: "allow-list"}); !IsErrorType(err, ErrorTypeInvalidConfig) {
+		t.Errorf("buildEnvironment() error = %v, want invalid_config", err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("pip", "...").Respond("", "", 0)
+
+	manager := NewManager(&Config{EnvironmentPolicy: "Clean"})
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.executePipCommandWithOutput("pip", []string{"list"}); !IsErrorType(err, ErrorTypeInvalidConfig) {
+		t.Errorf("executePipCommandWithOutput() error = %v, want invalid_config", err)
+	}
+	if len(runner.Calls()) != 0 {
+		t.Error("no command should run with an unknown environment policy")
+	}
+}
@@ -2,6 +2,7 @@ package pip
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -24,6 +25,9 @@ const (
 	ErrorTypeFeatureDisabled    ErrorType = "feature_disabled"
 	ErrorTypeTimeout            ErrorType = "timeout"
 	ErrorTypeInvalidConfig      ErrorType = "invalid_config"
+	ErrorTypeDependencyConflict ErrorType = "dependency_conflict"
+	ErrorTypeBuildFailed        ErrorType = "build_failed"
+	ErrorTypeNotInWheelhouse    ErrorType = "not_in_wheelhouse"
 )
 
 // PipErrorDetails provides additional context for errors
@@ -201,20 +205,65 @@ var (
 
 // IsErrorType checks if an error is of a specific type
 func IsErrorType(err error, errorType ErrorType) bool {
-	if pipErr, ok := err.(*PipErrorDetails); ok {
-		return pipErr.Type == errorType
-	}
-	return false
+	return GetErrorType(err) == errorType && errorType != ""
 }
 
-// GetErrorType returns the error type if it's a PipErrorDetails, otherwise returns empty string
+// GetErrorType returns the error type if it's a PipErrorDetails, PipError or
+// WheelhouseError, otherwise returns empty string
 func GetErrorType(err error) ErrorType {
-	if pipErr, ok := err.(*PipErrorDetails); ok {
+	switch pipErr := err.(type) {
+	case *PipErrorDetails:
 		return pipErr.Type
+	case *PipError:
+		return ErrorType(pipErr.Type)
+	case *WheelhouseError:
+		return ErrorTypeNotInWheelhouse
+	}
+	return ""
+}
+
+var (
+	// timeoutOutputRegex matches pip and urllib3 wording for a read or
+	// connect timeout. A bare "timed out" is not enough: build backends and
+	// setup.py subprocesses print it for failures that retrying won't fix.
+	timeoutOutputRegex = regexp.MustCompile(`(?i)(read timed out|the read operation timed out|` +
+		`connecttimeouterror|readtimeouterror|timed out.*\((connect|read) timeout)`)
+
+	// networkOutputRegex matches pip output reporting a transient network failure
+	networkOutputRegex = regexp.MustCompile(`(?i)(connection refused|connection reset|connection aborted|connection broken|` +
+		`newconnectionerror|failed to establish a new connection|temporary failure in name resolution|` +
+		`name or service not known|nodename nor servname|network is unreachable|max retries exceeded|` +
+		`remote end closed connection|remotedisconnected|incompleteread|proxyerror|` +
+		`(http error|status code|status|response) 5\d\d|5\d\d (server error|service unavailable|bad gateway|gateway time-?out|internal server error))`)
+)
+
+// ClassifyOutput inspects pip output for transient failures. It returns
+// ErrorTypeTimeout or ErrorTypeNetworkError when the output reports a timeout,
+// a connection failure or an HTTP 5xx response, and an empty string otherwise.
+//
+// Deterministic failures such as "No matching distribution found" are not
+// classified. Network markers take precedence over them because pip reports
+// no matching distribution after exhausting its own retries against an
+// unreachable index.
+func ClassifyOutput(output string) ErrorType {
+	if timeoutOutputRegex.MatchString(output) {
+		return ErrorTypeTimeout
+	}
+	if networkOutputRegex.MatchString(output) {
+		return ErrorTypeNetworkError
 	}
 	return ""
 }
 
+// IsTransientError reports whether an error is worth retrying
+func IsTransientError(err error) bool {
+	switch GetErrorType(err) {
+	case ErrorTypeNetworkError, ErrorTypeTimeout:
+		return true
+	}
+	return false
+}
+
 // WrapError wraps a generic error into a PipErrorDetails
 func WrapError(err error, errorType ErrorType, message string) *PipErrorDetails {
 	return &PipErrorDetails{
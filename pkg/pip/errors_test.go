@@ -482,3 +482,51 @@ func BenchmarkAddSuggestionsFromOutput(b *testing.B) {
 		err.addSuggestionsFromOutput(output)
 	}
 }
+
+func TestClassifyOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   ErrorType
+	}{
+		{"read timeout", "ReadTimeoutError: HTTPSConnectionPool(host='pypi.org', port=443): Read timed out.", ErrorTypeTimeout},
+		{"connect timeout", "Connection to pypi.org timed out. (connect timeout=15)", ErrorTypeTimeout},
+		{"socket timeout", "socket.timeout: The read operation timed out", ErrorTypeTimeout},
+		{"build timed out", "RuntimeError: compiler test timed out after 30 seconds\nerror: subprocess-exited-with-error", ""},
+		{"connection refused", "Failed to establish a new connection: [Errno 111] Connection refused", ErrorTypeNetworkError},
+		{"dns failure", "[Errno -3] Temporary failure in name resolution", ErrorTypeNetworkError},
+		{"http 503", "HTTP error 503 while getting https://pypi.org/simple/requests/", ErrorTypeNetworkError},
+		{"server error", "503 Server Error: Service Unavailable for url: https://pypi.org/simple/", ErrorTypeNetworkError},
+		{"no matching distribution", "ERROR: No matching distribution found for nonexistent-package", ""},
+		{"http 404", "HTTP error 404 while getting https://example.com/pkg.whl", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyOutput(tt.output); got != tt.want {
+				t.Errorf("ClassifyOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetErrorTypeForPipError(t *testing.T) {
+	err := &PipError{Type: string(ErrorTypeNetworkError), Message: "network"}
+
+	if GetErrorType(err) != ErrorTypeNetworkError {
+		t.Errorf("GetErrorType() = %q, want %q", GetErrorType(err), ErrorTypeNetworkError)
+	}
+	if !IsErrorType(err, ErrorTypeNetworkError) {
+		t.Error("IsErrorType should match PipError types")
+	}
+	if !IsTransientError(err) {
+		t.Error("network errors should be transient")
+	}
+	if IsTransientError(&PipError{Type: string(ErrorTypeCommandFailed)}) {
+		t.Error("command failures should not be transient")
+	}
+	if IsErrorType(errors.New("generic"), "") {
+		t.Error("IsErrorType should not match generic errors with an empty type")
+	}
+}
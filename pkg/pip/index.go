@@ -0,0 +1,572 @@
+package pip
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IndexCredentials are basic auth credentials for a package index host
+type IndexCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// IndexClient reads project metadata from a simple repository index using
+// the PEP 691 JSON API, falling back to PEP 503 HTML.
+//
+// Responses are cached in memory and revalidated with ETag and
+// Last-Modified headers. Credentials come from the index URL or from
+// Config.IndexCredentials, and hosts in Config.TrustedHosts are reached
+// without certificate verification, as pip does. These settings and
+// Config.Timeout are read on every request. An IndexClient is safe for
+// concurrent use.
+type IndexClient struct {
+	// MaxAge is how long a cached response is used without revalidating it.
+	// Zero revalidates every request.
+	MaxAge time.Duration
+
+	url     string
+	manager *Manager
+
+	mu         sync.Mutex
+	cache      map[string]*indexCacheEntry
+	client     *http.Client
+	clientConf indexClientConfig // the settings client was built with
+}
+
+// indexClientConfig holds the Config settings an index HTTP client is built
+// from, so the client is rebuilt when they change
+type indexClientConfig struct {
+	timeout time.Duration
+	trusted bool
+}
+
+// indexCacheEntry is a cached index response. Entries are never modified
+// once stored, so they can be read without holding IndexClient.mu.
+type indexCacheEntry struct {
+	body         []byte
+	contentType  string
+	etag         string
+	lastModified string
+	fetched      time.Time
+}
+
+// IndexProject is a project page on a simple repository index
+type IndexProject struct {
+	Name  string       `json:"name"`
+	Files []*IndexFile `json:"files"`
+
+	// Versions lists every version the index reports (PEP 700), or the
+	// versions parsed from the filenames when it reports none
+	Versions []string `json:"versions,omitempty"`
+}
+
+// IndexFile is a distribution file of a project
+type IndexFile struct {
+	Filename       string            `json:"filename"`
+	URL            string            `json:"url"` // absolute, without the hash fragment
+	Version        string            `json:"version,omitempty"`
+	Hashes         map[string]string `json:"hashes,omitempty"` // by algorithm, e.g. "sha256"
+	RequiresPython string            `json:"requires_python,omitempty"`
+	Yanked         bool              `json:"yanked,omitempty"`
+	YankedReason   string            `json:"yanked_reason,omitempty"`
+	Size           int64             `json:"size,omitempty"` // only reported by the JSON API
+
+	// CoreMetadata reports that the index serves the file's METADATA
+	// separately at URL + ".metadata" (PEP 658, PEP 714)
+	CoreMetadata       bool              `json:"core_metadata,omitempty"`
+	CoreMetadataHashes map[string]string `json:"core_metadata_hashes,omitempty"`
+}
+
+// SHA256 returns the file's sha256 digest, or "" when the index reports none
+func (f *IndexFile) SHA256() string {
+	return f.Hashes["sha256"]
+}
+
+// IsWheel reports whether the file is a wheel
+func (f *IndexFile) IsWheel() bool {
+	return strings.HasSuffix(f.Filename, ".whl")
+}
+
+// IndexRelease is the set of files published for one version
+type IndexRelease struct {
+	Version string       `json:"version"`
+	Files   []*IndexFile `json:"files"`
+
+	// Yanked is set when every file of the release is yanked
+	Yanked       bool   `json:"yanked,omitempty"`
+	YankedReason string `json:"yanked_reason,omitempty"`
+
+	// RequiresPython is the first requires-python declared by a file
+	RequiresPython string `json:"requires_python,omitempty"`
+}
+
+// IndexClient returns a client for the index at indexURL, or for
+// Config.DefaultIndex, then PyPI, when indexURL is empty. Clients are
+// cached per manager, so repeated calls share one response cache.
+func (m *Manager) IndexClient(indexURL string) *IndexClient {
+	if indexURL == "" {
+		indexURL = m.currentConfig().DefaultIndex
+	}
+	if indexURL == "" {
+		indexURL = defaultIndexURL
+	}
+	if !strings.HasSuffix(indexURL, "/") {
+		indexURL += "/"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if client := m.indexClients[indexURL]; client != nil {
+		return client
+	}
+
+	client := &IndexClient{
+		url:     indexURL,
+		manager: m,
+		cache:   make(map[string]*indexCacheEntry),
+	}
+	if m.indexClients == nil {
+		m.indexClients = make(map[string]*IndexClient)
+	}
+	m.indexClients[indexURL] = client
+	return client
+}
+
+// httpClient returns the HTTP client for a request to rawURL, rebuilding it
+// when Config.Timeout or Config.TrustedHosts changed since it was built
+func (c *IndexClient) httpClient(rawURL string) *http.Client {
+	config := c.manager.currentConfig()
+
+	conf := indexClientConfig{timeout: 30 * time.Second}
+	if config.Timeout > 0 {
+		conf.timeout = config.Timeout
+	}
+	if parsed, err := url.Parse(rawURL); err == nil {
+		conf.trusted = isTrustedHost(parsed.Host, config.TrustedHosts)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil || c.clientConf != conf {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if conf.trusted {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- host explicitly trusted
+		}
+		c.client = &http.Client{Timeout: conf.timeout, Transport: transport}
+		c.clientConf = conf
+	}
+	return c.client
+}
+
+// isTrustedHost reports whether host, with or without its port, is trusted
+func isTrustedHost(host string, trusted []string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	for _, entry := range trusted {
+		if strings.EqualFold(entry, host) || strings.EqualFold(entry, hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// URL returns the index URL, always ending in "/"
+func (c *IndexClient) URL() string {
+	return c.url
+}
+
+// Projects lists the names of every project on the index
+func (c *IndexClient) Projects() ([]string, error) {
+	body, contentType, err := c.get(c.url, simpleAcceptHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType == simpleJSONContentType {
+		var page struct {
+			Projects []struct {
+				Name string `json:"name"`
+			} `json:"projects"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, indexError(c.url, fmt.Errorf("invalid JSON: %w", err))
+		}
+
+		names := make([]string, 0, len(page.Projects))
+		for _, project := range page.Projects {
+			names = append(names, project.Name)
+		}
+		return names, nil
+	}
+
+	var names []string
+	for _, anchor := range parseAnchors(string(body)) {
+		if name := strings.TrimSpace(anchor.text); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Project reads the files of a project. It returns a package_not_found
+// error when the index does not have the project.
+func (c *IndexClient) Project(name string) (*IndexProject, error) {
+	if name == "" {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPackageSpec),
+			Message: "project name cannot be empty",
+		}
+	}
+
+	projectURL := c.url + normalizePackageName(name) + "/"
+	body, contentType, err := c.get(projectURL, simpleAcceptHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var project *IndexProject
+	if contentType == simpleJSONContentType {
+		project, err = parseJSONProject(projectURL, body)
+		if err != nil {
+			return nil, indexError(projectURL, err)
+		}
+	} else {
+		project = parseHTMLProject(projectURL, body)
+	}
+
+	if project.Name == "" {
+		project.Name = name
+	}
+	for _, file := range project.Files {
+		_, file.Version = splitDistFilename(file.Filename)
+	}
+	if len(project.Versions) == 0 {
+		for _, release := range project.Releases() {
+			project.Versions = append(project.Versions, release.Version)
+		}
+	}
+	return project, nil
+}
+
+// parseJSONProject parses a PEP 691 project page
+func parseJSONProject(pageURL string, body []byte) (*IndexProject, error) {
+	var page struct {
+		Name     string   `json:"name"`
+		Versions []string `json:"versions"`
+		Files    []struct {
+			Filename           string            `json:"filename"`
+			URL                string            `json:"url"`
+			Hashes             map[string]string `json:"hashes"`
+			RequiresPython     *string           `json:"requires-python"`
+			Yanked             json.RawMessage   `json:"yanked"`
+			Size               int64             `json:"size"`
+			CoreMetadata       json.RawMessage   `json:"core-metadata"`
+			DistInfoMetadata   json.RawMessage   `json:"dist-info-metadata"`
+			DataDistInfoLegacy json.RawMessage   `json:"data-dist-info-metadata"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	project := &IndexProject{Name: page.Name, Versions: page.Versions}
+	for _, raw := range page.Files {
+		file := &IndexFile{
+			Filename: raw.Filename,
+			URL:      resolveIndexURL(pageURL, raw.URL),
+			Hashes:   raw.Hashes,
+			Size:     raw.Size,
+		}
+		if raw.RequiresPython != nil {
+			file.RequiresPython = *raw.RequiresPython
+		}
+
+		// "yanked" is false, true or the reason
+		var reason string
+		if json.Unmarshal(raw.Yanked, &reason) == nil {
+			file.Yanked, file.YankedReason = true, reason
+		} else {
+			json.Unmarshal(raw.Yanked, &file.Yanked)
+		}
+
+		// "core-metadata" replaced "dist-info-metadata" in PEP 714; both
+		// are false, true or a hash dictionary
+		for _, metadata := range []json.RawMessage{raw.CoreMetadata, raw.DistInfoMetadata, raw.DataDistInfoLegacy} {
+			if len(metadata) == 0 {
+				continue
+			}
+			var hashes map[string]string
+			if json.Unmarshal(metadata, &hashes) == nil {
+				file.CoreMetadata, file.CoreMetadataHashes = true, hashes
+			} else {
+				json.Unmarshal(metadata, &file.CoreMetadata)
+			}
+			break
+		}
+
+		project.Files = append(project.Files, file)
+	}
+	return project, nil
+}
+
+// parseHTMLProject parses a PEP 503 project page
+func parseHTMLProject(pageURL string, body []byte) *IndexProject {
+	project := &IndexProject{}
+	for _, anchor := range parseAnchors(string(body)) {
+		href, fragment := anchor.attrs["href"], ""
+		if idx := strings.Index(href, "#"); idx >= 0 {
+			href, fragment = href[:idx], href[idx+1:]
+		}
+
+		file := &IndexFile{
+			Filename:       strings.TrimSpace(anchor.text),
+			URL:            resolveIndexURL(pageURL, href),
+			RequiresPython: anchor.attrs["data-requires-python"],
+		}
+		if algorithm, digest := hashFragment(fragment); algorithm != "" {
+			file.Hashes = map[string]string{algorithm: digest}
+		}
+		if reason, ok := anchor.attrs["data-yanked"]; ok {
+			file.Yanked, file.YankedReason = true, reason
+		}
+
+		for _, attr := range []string{"data-core-metadata", "data-dist-info-metadata"} {
+			value, ok := anchor.attrs[attr]
+			if !ok || value == "false" {
+				continue
+			}
+			file.CoreMetadata = true
+			if algorithm, digest := hashFragment(value); algorithm != "" {
+				file.CoreMetadataHashes = map[string]string{algorithm: digest}
+			}
+			break
+		}
+
+		project.Files = append(project.Files, file)
+	}
+	return project
+}
+
+// hashFragment splits "sha256=<digest>"
+func hashFragment(fragment string) (string, string) {
+	parts := strings.SplitN(fragment, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", ""
+	}
+	return strings.ToLower(parts[0]), parts[1]
+}
+
+// resolveIndexURL resolves a possibly relative link on an index page
+func resolveIndexURL(pageURL, link string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	resolved := base.ResolveReference(ref)
+	resolved.Fragment = ""
+	resolved.User = nil
+	return resolved.String()
+}
+
+// Releases groups the project's files by version, oldest first by PEP 440
+// ordering. Files whose version cannot be parsed are left out.
+func (p *IndexProject) Releases() []*IndexRelease {
+	byVersion := make(map[string]*IndexRelease)
+	parsed := make(map[string]*Version)
+
+	for _, file := range p.Files {
+		version, err := ParseVersion(file.Version)
+		if err != nil {
+			continue
+		}
+		key := version.String()
+		release := byVersion[key]
+		if release == nil {
+			release = &IndexRelease{Version: key, Yanked: true}
+			byVersion[key] = release
+			parsed[key] = version
+		}
+
+		release.Files = append(release.Files, file)
+		if !file.Yanked {
+			release.Yanked, release.YankedReason = false, ""
+		} else if release.Yanked && release.YankedReason == "" {
+			release.YankedReason = file.YankedReason
+		}
+		if release.RequiresPython == "" {
+			release.RequiresPython = file.RequiresPython
+		}
+	}
+
+	releases := make([]*IndexRelease, 0, len(byVersion))
+	for _, release := range byVersion {
+		releases = append(releases, release)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return parsed[releases[i].Version].Compare(parsed[releases[j].Version]) < 0
+	})
+	return releases
+}
+
+// Release returns the release equal to version under PEP 440, so "1.0"
+// finds "1.0.0", or nil when the project has no such release
+func (p *IndexProject) Release(version string) *IndexRelease {
+	want, err := ParseVersion(version)
+	if err != nil {
+		return nil
+	}
+	for _, release := range p.Releases() {
+		if MustParseVersion(release.Version).Compare(want) == 0 {
+			return release
+		}
+	}
+	return nil
+}
+
+// Latest returns the newest release that is not yanked, preferring final
+// releases over pre-releases, or nil when there is none
+func (p *IndexProject) Latest() *IndexRelease {
+	var latestPre *IndexRelease
+	releases := p.Releases()
+	for i := len(releases) - 1; i >= 0; i-- {
+		release := releases[i]
+		if release.Yanked {
+			continue
+		}
+		if !MustParseVersion(release.Version).IsPrerelease() {
+			return release
+		}
+		if latestPre == nil {
+			latestPre = release
+		}
+	}
+	return latestPre
+}
+
+// projectInfo reads a project's JSON metadata endpoint
+// ("<base>/pypi/<name>/json"), which PyPI and most mirrors serve
+func (c *IndexClient) projectInfo(name string) (*projectInfo, error) {
+	metadataURL := projectJSONURL(c.url, name)
+	body, _, err := c.get(metadataURL, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	var page struct {
+		Info projectInfo `json:"info"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, indexError(metadataURL, fmt.Errorf("invalid JSON: %w", err))
+	}
+	if page.Info.Version == "" {
+		return nil, indexError(metadataURL, fmt.Errorf("no version in metadata"))
+	}
+	return &page.Info, nil
+}
+
+// get fetches rawURL, serving it from the cache when it is fresh or the
+// index reports it unchanged, and returns the body and its media type
+func (c *IndexClient) get(rawURL, accept string) ([]byte, string, error) {
+	key := accept + " " + rawURL
+
+	c.mu.Lock()
+	cached := c.cache[key]
+	c.mu.Unlock()
+
+	if cached != nil && c.MaxAge > 0 && time.Since(cached.fetched) < c.MaxAge {
+		return cached.body, cached.contentType, nil
+	}
+
+	req, err := http.NewRequestWithContext(c.manager.context(), http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", indexError(rawURL, err)
+	}
+	req.Header.Set("Accept", accept)
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	if req.URL.User == nil {
+		if credentials, ok := c.manager.currentConfig().IndexCredentials[req.URL.Hostname()]; ok {
+			req.SetBasicAuth(credentials.Username, credentials.Password)
+		}
+	}
+
+	resp, err := c.httpClient(rawURL).Do(req)
+	if err != nil {
+		return nil, "", indexError(rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		revalidated := *cached
+		revalidated.fetched = time.Now()
+		c.mu.Lock()
+		c.cache[key] = &revalidated
+		c.mu.Unlock()
+		return cached.body, cached.contentType, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		pipErr := indexError(rawURL, fmt.Errorf("HTTP %d", resp.StatusCode))
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			pipErr.Type = string(ErrorTypePackageNotFound)
+		case http.StatusUnauthorized, http.StatusForbidden:
+			pipErr.Type = string(ErrorTypePermissionDenied)
+		}
+		return nil, "", pipErr
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", indexError(rawURL, err)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	entry := &indexCacheEntry{
+		body:         body,
+		contentType:  mediaType,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		fetched:      time.Now(),
+	}
+	if entry.etag != "" || entry.lastModified != "" || c.MaxAge > 0 {
+		c.mu.Lock()
+		c.cache[key] = entry
+		c.mu.Unlock()
+	}
+
+	return body, mediaType, nil
+}
+
+// indexError wraps a failed index request as a network error
+func indexError(rawURL string, err error) *PipError {
+	return &PipError{
+		Type:    string(ErrorTypeNetworkError),
+		Message: redactValue(fmt.Sprintf("index request %s failed: %v", rawURL, err)),
+	}
+}
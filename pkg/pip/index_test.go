@@ -0,0 +1,290 @@
+package pip
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testProjectJSON = `{
+  "meta": {"api-version": "1.1"},
+  "name": "demo",
+  "versions": ["1.0", "1.1", "2.0rc1"],
+  "files": [
+    {"filename": "demo-1.0.tar.gz", "url": "../../files/demo-1.0.tar.gz", "hashes": {"sha256": "aaa"}, "requires-python": ">=3.7", "yanked": false, "size": 120},
+    {"filename": "demo-1.1-py3-none-any.whl", "url": "https://files.example.com/demo-1.1-py3-none-any.whl", "hashes": {"sha256": "bbb"}, "requires-python": ">=3.8", "yanked": "broken import", "core-metadata": {"sha256": "ccc"}},
+    {"filename": "demo-2.0rc1-py3-none-any.whl", "url": "../../files/demo-2.0rc1-py3-none-any.whl", "hashes": {}, "requires-python": null, "yanked": false, "dist-info-metadata": true}
+  ]
+}`
+
+const testProjectHTML = `<!DOCTYPE html><html><body>
+<a href="../../files/demo-1.0.tar.gz#sha256=aaa" data-requires-python="&gt;=3.7">demo-1.0.tar.gz</a>
+<a href="https://files.example.com/demo-1.1-py3-none-any.whl#sha256=bbb" data-requires-python="&gt;=3.8" data-yanked="broken import" data-dist-info-metadata="sha256=ccc">demo-1.1-py3-none-any.whl</a>
+<a href="../../files/demo-2.0rc1-py3-none-any.whl" data-core-metadata="true">demo-2.0rc1-py3-none-any.whl</a>
+</body></html>`
+
+// newProjectIndex serves testProjectJSON or testProjectHTML for "demo" and
+// counts the requests that reached it
+func newProjectIndex(t *testing.T, jsonAPI bool, requests *int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if r.URL.Path != "/simple/demo/" {
+			http.NotFound(w, r)
+			return
+		}
+		if jsonAPI {
+			w.Header().Set("Content-Type", simpleJSONContentType)
+			fmt.Fprint(w, testProjectJSON)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, testProjectHTML)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIndexClientProject(t *testing.T) {
+	for _, jsonAPI := range []bool{true, false} {
+		t.Run(fmt.Sprintf("json=%v", jsonAPI), func(t *testing.T) {
+			var requests int32
+			server := newProjectIndex(t, jsonAPI, &requests)
+
+			manager := NewManager(&Config{DefaultIndex: server.URL + "/simple"})
+			project, err := manager.IndexClient("").Project("Demo")
+			if err != nil {
+				t.Fatalf("Project() error: %v", err)
+			}
+			if len(project.Files) != 3 {
+				t.Fatalf("files = %d, want 3", len(project.Files))
+			}
+
+			sdist, wheel, pre := project.Files[0], project.Files[1], project.Files[2]
+			if sdist.URL != server.URL+"/files/demo-1.0.tar.gz" || sdist.SHA256() != "aaa" || sdist.RequiresPython != ">=3.7" {
+				t.Errorf("sdist = %+v", sdist)
+			}
+			if sdist.Version != "1.0" || sdist.IsWheel() || sdist.Yanked || sdist.CoreMetadata {
+				t.Errorf("sdist = %+v", sdist)
+			}
+			if wheel.URL != "https://files.example.com/demo-1.1-py3-none-any.whl" || wheel.SHA256() != "bbb" {
+				t.Errorf("wheel = %+v", wheel)
+			}
+			if !wheel.Yanked || wheel.YankedReason != "broken import" {
+				t.Errorf("wheel yanked = %v %q", wheel.Yanked, wheel.YankedReason)
+			}
+			if !wheel.CoreMetadata || wheel.CoreMetadataHashes["sha256"] != "ccc" {
+				t.Errorf("wheel metadata = %v %v", wheel.CoreMetadata, wheel.CoreMetadataHashes)
+			}
+			if !pre.CoreMetadata || pre.SHA256() != "" || pre.RequiresPython != "" {
+				t.Errorf("pre-release = %+v", pre)
+			}
+
+			if strings.Join(project.Versions, ",") != "1.0,1.1,2.0rc1" {
+				t.Errorf("versions = %v", project.Versions)
+			}
+		})
+	}
+}
+
+func TestIndexProjectReleases(t *testing.T) {
+	project := &IndexProject{Files: []*IndexFile{
+		{Filename: "demo-1.10.tar.gz", Version: "1.10"},
+		{Filename: "demo-1.9-py3-none-any.whl", Version: "1.9", Yanked: true, YankedReason: "bad wheel"},
+		{Filename: "demo-1.9.tar.gz", Version: "1.9", RequiresPython: ">=3.8"},
+		{Filename: "demo-2.0.tar.gz", Version: "2.0", Yanked: true, YankedReason: "security"},
+		{Filename: "demo-2.1b1.tar.gz", Version: "2.1b1"},
+		{Filename: "demo-latest.zip", Version: "latest"},
+	}}
+
+	var versions []string
+	for _, release := range project.Releases() {
+		versions = append(versions, release.Version)
+	}
+	if strings.Join(versions, ",") != "1.9,1.10,2.0,2.1b1" {
+		t.Fatalf("releases = %v", versions)
+	}
+
+	partial := project.Release("1.9.0")
+	if partial == nil || partial.Yanked || len(partial.Files) != 2 || partial.RequiresPython != ">=3.8" {
+		t.Errorf("Release(1.9.0) = %+v", partial)
+	}
+	yanked := project.Release("2.0")
+	if yanked == nil || !yanked.Yanked || yanked.YankedReason != "security" {
+		t.Errorf("Release(2.0) = %+v", yanked)
+	}
+	if project.Release("3.0") != nil {
+		t.Error("Release(3.0) should be nil")
+	}
+
+	if latest := project.Latest(); latest == nil || latest.Version != "1.10" {
+		t.Errorf("Latest() = %+v, want 1.10", latest)
+	}
+}
+
+func TestIndexClientRevalidation(t *testing.T) {
+	var requests, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", simpleJSONContentType)
+		fmt.Fprint(w, testProjectJSON)
+	}))
+	defer server.Close()
+
+	client := NewManager(nil).IndexClient(server.URL + "/simple/")
+	for i := 0; i < 3; i++ {
+		project, err := client.Project("demo")
+		if err != nil {
+			t.Fatalf("Project() error: %v", err)
+		}
+		if len(project.Files) != 3 {
+			t.Fatalf("request %d: files = %d, want 3", i, len(project.Files))
+		}
+	}
+	if requests != 3 || notModified != 2 {
+		t.Errorf("requests = %d, not modified = %d; want 3 and 2", requests, notModified)
+	}
+
+	client.MaxAge = time.Hour
+	if _, err := client.Project("demo"); err != nil {
+		t.Fatalf("Project() error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("fresh cache entry was revalidated")
+	}
+}
+
+func TestIndexClientConcurrentRevalidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", simpleJSONContentType)
+		fmt.Fprint(w, testProjectJSON)
+	}))
+	defer server.Close()
+
+	client := NewManager(nil).IndexClient(server.URL + "/simple/")
+	client.MaxAge = time.Millisecond
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if _, err := client.Project("demo"); err != nil {
+					t.Errorf("Project() error: %v", err)
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestIndexClientFollowsConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", simpleJSONContentType)
+		fmt.Fprint(w, testProjectJSON)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&Config{DefaultIndex: server.URL + "/simple/"})
+	client := manager.IndexClient("")
+	if _, err := client.Project("demo"); err == nil {
+		t.Fatal("untrusted self-signed index should fail certificate verification")
+	}
+
+	// A client obtained before the change picks up the new settings
+	manager.SetConfig(&Config{DefaultIndex: server.URL + "/simple/", TrustedHosts: []string{"127.0.0.1"}})
+	if _, err := client.Project("demo"); err != nil {
+		t.Fatalf("trusted host: error = %v", err)
+	}
+}
+
+func TestIndexClientCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "ci" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", simpleJSONContentType)
+		fmt.Fprint(w, testProjectJSON)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&Config{DefaultIndex: server.URL + "/simple/"})
+	_, err := manager.IndexClient("").Project("demo")
+	if pipErr, ok := err.(*PipError); !ok || pipErr.Type != string(ErrorTypePermissionDenied) {
+		t.Fatalf("without credentials: error = %v, want permission_denied", err)
+	}
+
+	manager.SetConfig(&Config{
+		DefaultIndex:     server.URL + "/simple/",
+		IndexCredentials: map[string]IndexCredentials{"127.0.0.1": {Username: "ci", Password: "secret"}},
+	})
+	if _, err := manager.IndexClient("").Project("demo"); err != nil {
+		t.Fatalf("with credentials: error = %v", err)
+	}
+}
+
+func TestIndexClientNotFound(t *testing.T) {
+	var requests int32
+	server := newProjectIndex(t, true, &requests)
+
+	_, err := NewManager(nil).IndexClient(server.URL + "/simple").Project("missing")
+	if pipErr, ok := err.(*PipError); !ok || pipErr.Type != string(ErrorTypePackageNotFound) {
+		t.Fatalf("error = %v, want package_not_found", err)
+	}
+}
+
+func TestManagerIndexClientCache(t *testing.T) {
+	manager := NewManager(&Config{DefaultIndex: "https://mirror.example.com/simple"})
+
+	client := manager.IndexClient("")
+	if client.URL() != "https://mirror.example.com/simple/" {
+		t.Errorf("URL() = %q", client.URL())
+	}
+	if manager.IndexClient("https://mirror.example.com/simple/") != client {
+		t.Error("IndexClient should return the cached client")
+	}
+	if NewManager(nil).IndexClient("").URL() != defaultIndexURL {
+		t.Error("IndexClient should default to PyPI")
+	}
+
+	manager.SetConfig(DefaultConfig())
+	if manager.IndexClient("https://mirror.example.com/simple/") == client {
+		t.Error("SetConfig should drop cached clients")
+	}
+}
+
+func TestIsTrustedHost(t *testing.T) {
+	trusted := []string{"mirror.internal", "nexus.example.com:8443"}
+	for host, want := range map[string]bool{
+		"mirror.internal":        true,
+		"mirror.internal:443":    true,
+		"nexus.example.com:8443": true,
+		"nexus.example.com":      false,
+		"pypi.org":               false,
+	} {
+		if got := isTrustedHost(host, trusted); got != want {
+			t.Errorf("isTrustedHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package pip
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// InterpreterInfo is the result of introspecting a Python interpreter
+type InterpreterInfo struct {
+	Executable     string         `json:"executable"`
+	Version        string         `json:"version"` // e.g. "3.11.4"
+	VersionInfo    VersionInfo    `json:"version_info"`
+	Implementation string         `json:"implementation"` // sys.implementation.name, e.g. "cpython"
+	Prefix         string         `json:"prefix"`
+	BasePrefix     string         `json:"base_prefix"`
+	InVenv         bool           `json:"in_venv"`
+	Paths          SysconfigPaths `json:"paths"`
+	Platform       string         `json:"platform"` // sysconfig.get_platform(), e.g. "linux-x86_64"
+
+	// Tags are the supported "interpreter-abi-platform" wheel tags, most
+	// preferred first. PlatformTags and ABITags list their unique parts.
+	Tags         []string `json:"tags"`
+	PlatformTags []string `json:"platform_tags"`
+	ABITags      []string `json:"abi_tags"`
+}
+
+// VersionInfo mirrors sys.version_info
+type VersionInfo struct {
+	Major        int    `json:"major"`
+	Minor        int    `json:"minor"`
+	Micro        int    `json:"micro"`
+	ReleaseLevel string `json:"releaselevel"` // "alpha", "beta", "candidate" or "final"
+	Serial       int    `json:"serial"`
+}
+
+// SysconfigPaths holds the installation scheme paths from sysconfig.get_paths()
+type SysconfigPaths struct {
+	Purelib string `json:"purelib"`
+	Platlib string `json:"platlib"`
+	Scripts string `json:"scripts"`
+	Include string `json:"include"`
+	Data    string `json:"data"`
+	Stdlib  string `json:"stdlib"`
+}
+
+// inspectScript prints InterpreterInfo as JSON. Wheel tags come from the
+// packaging library vendored by pip when available, falling back to the
+// interpreter's own tag for bare interpreters without pip.
+const inspectScript = `
+import json, platform, sys, sysconfig
+
+try:
+    from pip._vendor.packaging import tags as _tags
+except Exception:
+    try:
+        from packaging import tags as _tags
+    except Exception:
+        _tags = None
+
+if _tags is not None:
+    tags = [str(t) for t in _tags.sys_tags()]
+else:
+    cpython = platform.python_implementation() == "CPython"
+    interp = ("cp" if cpython else "py") + "%d%d" % sys.version_info[:2]
+    plat = sysconfig.get_platform().replace("-", "_").replace(".", "_")
+    tags = ["%s-%s-%s" % (interp, interp, plat)] if cpython else []
+    tags += ["%s-none-%s" % (interp, plat), "py%d-none-any" % sys.version_info[0]]
+
+base_prefix = getattr(sys, "base_prefix", getattr(sys, "real_prefix", sys.prefix))
+paths = sysconfig.get_paths()
+impl = getattr(sys, "implementation", None)
+
+print(json.dumps({
+    "executable": sys.executable,
+    "version": platform.python_version(),
+    "version_info": dict(zip(["major", "minor", "micro", "releaselevel", "serial"], list(sys.version_info))),
+    "implementation": impl.name if impl else platform.python_implementation().lower(),
+    "prefix": sys.prefix,
+    "base_prefix": base_prefix,
+    "in_venv": sys.prefix != base_prefix or hasattr(sys, "real_prefix"),
+    "paths": dict((k, paths.get(k, "")) for k in ["purelib", "platlib", "scripts", "include", "data", "stdlib"]),
+    "platform": sysconfig.get_platform(),
+    "tags": tags,
+}))
+`
+
+// Inspect runs the interpreter once and returns its paths, platform and
+// supported wheel tags. Interpreters returned by DiscoverInterpreters run
+// through their Manager's command runner.
+func (i *Interpreter) Inspect() (*InterpreterInfo, error) {
+	manager := i.manager
+	if manager == nil {
+		manager = NewManager(nil)
+	}
+	return manager.InspectInterpreter(i.Path)
+}
+
+// InspectInterpreter introspects the Python interpreter at python
+func (m *Manager) InspectInterpreter(python string) (*InterpreterInfo, error) {
+	m.logDebug("Inspecting Python interpreter: %s", python)
+
+	cmd := &Command{Name: python, Args: []string{"-c", inspectScript}}
+	result, err := m.runCommand(cmd)
+	if err != nil {
+		return nil, m.createPipError(cmd.String(), result.Output, result.ExitCode, err)
+	}
+
+	var info InterpreterInfo
+	if err := json.Unmarshal([]byte(strings.TrimSpace(result.Stdout)), &info); err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypeCommandFailed),
+			Message: fmt.Sprintf("unexpected interpreter introspection output: %v", err),
+			Command: cmd.String(),
+			Output:  result.Output,
+		}
+	}
+
+	info.ABITags, info.PlatformTags = splitTags(info.Tags)
+	return &info, nil
+}
+
+// splitTags returns the unique ABI and platform parts of wheel tags in order
+func splitTags(tags []string) (abis, platforms []string) {
+	for _, tag := range tags {
+		parts := strings.SplitN(tag, "-", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if !containsString(abis, parts[1]) {
+			abis = append(abis, parts[1])
+		}
+		if !containsString(platforms, parts[2]) {
+			platforms = append(platforms, parts[2])
+		}
+	}
+	return abis, platforms
+}
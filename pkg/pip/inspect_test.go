@@ -0,0 +1,112 @@
+package pip
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+const fakeInspectOutput = `{"executable": "/venv/bin/python", "version": "3.11.4",
+"version_info": {"major": 3, "minor": 11, "micro": 4, "releaselevel": "final", "serial": 0},
+"implementation": "cpython", "prefix": "/venv", "base_prefix": "/usr", "in_venv": true,
+"paths": {"purelib": "/venv/lib/python3.11/site-packages", "platlib": "/venv/lib64/python3.11/site-packages",
+"scripts": "/venv/bin", "include": "/usr/include/python3.11", "data": "/venv", "stdlib": "/usr/lib/python3.11"},
+"platform": "linux-x86_64",
+"tags": ["cp311-cp311-manylinux_2_17_x86_64", "cp311-abi3-manylinux_2_17_x86_64", "cp311-none-linux_x86_64", "py3-none-any"]}`
+
+func TestInspectInterpreter(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("/venv/bin/python", "-c", "*").Respond(fakeInspectOutput, "", 0)
+	runner.On("/broken/python", "-c", "*").Respond("Traceback", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	info, err := manager.InspectInterpreter("/venv/bin/python")
+	if err != nil {
+		t.Fatalf("InspectInterpreter() error: %v", err)
+	}
+
+	if info.VersionInfo.Minor != 11 || info.VersionInfo.ReleaseLevel != "final" || !info.InVenv {
+		t.Errorf("InspectInterpreter() = %+v", info)
+	}
+	if info.Paths.Purelib != "/venv/lib/python3.11/site-packages" || info.Paths.Scripts != "/venv/bin" {
+		t.Errorf("Paths = %+v", info.Paths)
+	}
+	if want := []string{"cp311", "abi3", "none"}; !reflect.DeepEqual(info.ABITags, want) {
+		t.Errorf("ABITags = %v, want %v", info.ABITags, want)
+	}
+	if want := []string{"manylinux_2_17_x86_64", "linux_x86_64", "any"}; !reflect.DeepEqual(info.PlatformTags, want) {
+		t.Errorf("PlatformTags = %v, want %v", info.PlatformTags, want)
+	}
+
+	if _, err := manager.InspectInterpreter("/broken/python"); err == nil {
+		t.Error("InspectInterpreter() should fail on unexpected output")
+	}
+
+	interpreter := &Interpreter{Path: "/venv/bin/python", manager: manager}
+	if info, err := interpreter.Inspect(); err != nil || info.Prefix != "/venv" {
+		t.Errorf("Interpreter.Inspect() = %+v, %v", info, err)
+	}
+}
+
+func TestGetVenvInfoUsesInterpreterPaths(t *testing.T) {
+	manager := NewManager(nil)
+	dir := t.TempDir()
+	createFakeVenv(t, manager, dir)
+
+	runner := NewFakeRunner()
+	runner.On("python*", "-c", "*").Respond(fakeInspectOutput, "", 0)
+	manager.SetCommandRunner(runner)
+
+	info, err := manager.GetVenvInfo(dir)
+	if err != nil {
+		t.Fatalf("GetVenvInfo() error: %v", err)
+	}
+	if info.PythonVersion != "3.11.4" || info.SitePackages != "/venv/lib/python3.11/site-packages" || info.ScriptsPath != "/venv/bin" {
+		t.Errorf("GetVenvInfo() = %+v", info)
+	}
+}
+
+func TestGetVenvBinPathLayout(t *testing.T) {
+	manager := NewManager(nil)
+	dir := t.TempDir()
+
+	preferred, other := "bin", "Scripts"
+	if runtime.GOOS == "windows" {
+		preferred, other = other, preferred
+	}
+
+	if got := manager.getVenvBinPath(dir); got != filepath.Join(dir, preferred) {
+		t.Errorf("getVenvBinPath() = %s, want %s", got, filepath.Join(dir, preferred))
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, other), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := manager.getVenvBinPath(dir); got != filepath.Join(dir, other) {
+		t.Errorf("getVenvBinPath() = %s, want %s for the alternate layout", got, filepath.Join(dir, other))
+	}
+}
+
+func TestInspectRealInterpreter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping real interpreter test in short mode")
+	}
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not available")
+	}
+
+	info, err := NewManager(nil).InspectInterpreter(python)
+	if err != nil {
+		t.Fatalf("InspectInterpreter() error: %v", err)
+	}
+	if info.VersionInfo.Major != 3 || info.Paths.Purelib == "" || len(info.Tags) == 0 {
+		t.Errorf("InspectInterpreter() = %+v", info)
+	}
+}
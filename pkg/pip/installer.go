@@ -5,12 +5,15 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 )
 
+// getPipURL is the bootstrap script used when ensurepip is unavailable
+const getPipURL = "https://bootstrap.pypa.io/get-pip.py"
+
 // Installer handles pip installation across different operating systems
 type Installer struct {
 	manager *Manager
@@ -34,45 +37,61 @@ func (m *Manager) IsInstalled() (bool, error) {
 	}
 
 	// Try to run pip --version
-	cmd := exec.CommandContext(m.ctx, pipPath, "--version")
-	output, err := cmd.Output()
+	result, err := m.runCommand(m.pipCommand(pipPath, "--version"))
 	if err != nil {
 		m.logDebug("pip --version failed: %v", err)
 		return false, nil
 	}
 
-	m.logDebug("pip version output: %s", string(output))
+	m.logDebug("pip version output: %s", result.Stdout)
 	return true, nil
 }
 
-// findPipExecutable finds the pip executable path
+// findPipExecutable finds the pip executable path.
+//
+// Unless Config.PipPath is set, pip runs as "<python> -m pip" for the
+// interpreter chosen by findPythonExecutable, so packages are installed where
+// that interpreter looks for them. A pip on PATH is only used when no
+// interpreter can be found.
 func (m *Manager) findPipExecutable() (string, error) {
 	// If pip path is configured, use it
-	if m.config.PipPath != "" {
-		if _, err := os.Stat(m.config.PipPath); err == nil {
-			return m.config.PipPath, nil
+	config := m.currentConfig()
+	if config.PipPath != "" {
+		if _, err := os.Stat(config.PipPath); err == nil {
+			return config.PipPath, nil
 		}
-	}
 
-	// Try common pip command names
-	pipCommands := []string{"pip", "pip3", "python -m pip", "python3 -m pip"}
-
-	for _, cmd := range pipCommands {
-		parts := strings.Fields(cmd)
-		if len(parts) == 0 {
-			continue
+		// "<python> -m pip" is valid when the interpreter exists
+		if python := strings.TrimSuffix(config.PipPath, pipModuleSuffix); python != config.PipPath {
+			if _, err := os.Stat(python); err == nil {
+				return config.PipPath, nil
+			}
+			if _, err := m.lookPath(python); err == nil {
+				return config.PipPath, nil
+			}
 		}
 
-		// Check if command exists
-		if len(parts) == 1 {
-			if path, err := exec.LookPath(parts[0]); err == nil {
+		// A bare command name such as "pip3" is resolved on PATH
+		if !strings.ContainsAny(config.PipPath, `/\ `) {
+			if path, err := m.lookPath(config.PipPath); err == nil {
 				return path, nil
 			}
-		} else {
-			// For commands like "python -m pip"
-			if _, err := exec.LookPath(parts[0]); err == nil {
-				return cmd, nil
-			}
+		}
+	}
+
+	// Bind pip to the selected interpreter
+	python, err := m.findPythonExecutable()
+	if err == nil {
+		return python + pipModuleSuffix, nil
+	}
+	if config.PythonVersion != "" {
+		return "", err
+	}
+
+	// Without an interpreter, fall back to a pip on PATH
+	for _, name := range []string{"pip", "pip3"} {
+		if path, err := m.lookPath(name); err == nil {
+			return path, nil
 		}
 	}
 
@@ -82,17 +101,27 @@ func (m *Manager) findPipExecutable() (string, error) {
 // findPythonExecutable finds the Python executable path
 func (m *Manager) findPythonExecutable() (string, error) {
 	// If python path is configured, use it
-	if m.config.PythonPath != "" {
-		if _, err := os.Stat(m.config.PythonPath); err == nil {
-			return m.config.PythonPath, nil
+	config := m.currentConfig()
+	if config.PythonPath != "" {
+		if _, err := os.Stat(config.PythonPath); err == nil {
+			return config.PythonPath, nil
 		}
 	}
 
+	// Otherwise select the newest interpreter matching the version constraint
+	if config.PythonVersion != "" {
+		interpreter, err := m.FindInterpreter(config.PythonVersion)
+		if err != nil {
+			return "", err
+		}
+		return interpreter.Path, nil
+	}
+
 	// Try common python command names
 	pythonCommands := []string{"python", "python3", "py"}
 
 	for _, cmd := range pythonCommands {
-		if path, err := exec.LookPath(cmd); err == nil {
+		if path, err := m.lookPath(cmd); err == nil {
 			return path, nil
 		}
 	}
@@ -129,8 +158,8 @@ func (i *Installer) installOnWindows() error {
 	}
 
 	// Check if pip is already available through python -m pip
-	cmd := exec.CommandContext(i.manager.ctx, pythonPath, "-m", "pip", "--version")
-	if err := cmd.Run(); err == nil {
+	cmd := &Command{Name: pythonPath, Args: []string{"-m", "pip", "--version"}}
+	if _, err := i.manager.runCommand(cmd); err == nil {
 		i.manager.logInfo("pip is already available through python -m pip")
 		return nil
 	}
@@ -159,8 +188,8 @@ func (i *Installer) installOnMacOS() error {
 	}
 
 	// Check if pip is already available
-	cmd := exec.CommandContext(i.manager.ctx, pythonPath, "-m", "pip", "--version")
-	if err := cmd.Run(); err == nil {
+	cmd := &Command{Name: pythonPath, Args: []string{"-m", "pip", "--version"}}
+	if _, err := i.manager.runCommand(cmd); err == nil {
 		i.manager.logInfo("pip is already available through python -m pip")
 		return nil
 	}
@@ -190,8 +219,8 @@ func (i *Installer) installOnLinux() error {
 	}
 
 	// Check if pip is already available
-	cmd := exec.CommandContext(i.manager.ctx, pythonPath, "-m", "pip", "--version")
-	if err := cmd.Run(); err == nil {
+	cmd := &Command{Name: pythonPath, Args: []string{"-m", "pip", "--version"}}
+	if _, err := i.manager.runCommand(cmd); err == nil {
 		i.manager.logInfo("pip is already available through python -m pip")
 		return nil
 	}
@@ -222,11 +251,11 @@ func (i *Installer) installUsingPackageManager() error {
 	}
 
 	for _, pm := range packageManagers {
-		if _, err := exec.LookPath(pm.cmd); err == nil {
+		if _, err := i.manager.lookPath(pm.cmd); err == nil {
 			i.manager.logInfo("Found package manager: %s", pm.cmd)
 
-			cmd := exec.CommandContext(i.manager.ctx, "sudo", append([]string{pm.cmd}, pm.args...)...)
-			if err := cmd.Run(); err == nil {
+			cmd := &Command{Name: "sudo", Args: append([]string{pm.cmd}, pm.args...)}
+			if _, err := i.manager.runPlanned(cmd, "install pip with "+pm.cmd); err == nil {
 				i.manager.logInfo("Successfully installed pip using %s", pm.cmd)
 				return nil
 			}
@@ -241,24 +270,24 @@ func (i *Installer) installPythonWithHomebrew() error {
 	i.manager.logInfo("Trying to install Python using Homebrew")
 
 	// Check if Homebrew is available
-	if _, err := exec.LookPath("brew"); err != nil {
+	if _, err := i.manager.lookPath("brew"); err != nil {
 		return fmt.Errorf("Homebrew not found")
 	}
 
 	// Install Python
-	cmd := exec.CommandContext(i.manager.ctx, "brew", "install", "python")
-	return cmd.Run()
+	_, err := i.manager.runPlanned(&Command{Name: "brew", Args: []string{"install", "python"}}, "install Python with Homebrew")
+	return err
 }
 
 // installUsingEnsurepip installs pip using Python's ensurepip module
 func (i *Installer) installUsingEnsurepip(pythonPath string) error {
 	i.manager.logInfo("Trying to install pip using ensurepip")
 
-	cmd := exec.CommandContext(i.manager.ctx, pythonPath, "-m", "ensurepip", "--upgrade")
-	output, err := cmd.CombinedOutput()
+	cmd := &Command{Name: pythonPath, Args: []string{"-m", "ensurepip", "--upgrade"}}
+	result, err := i.manager.runPlanned(cmd, "install pip with ensurepip")
 
 	if err != nil {
-		i.manager.logError("ensurepip failed: %v, output: %s", err, string(output))
+		i.manager.logError("ensurepip failed: %v, output: %s", err, result.Output)
 		return err
 	}
 
@@ -270,6 +299,13 @@ func (i *Installer) installUsingEnsurepip(pythonPath string) error {
 func (i *Installer) installUsingGetPip(pythonPath string) error {
 	i.manager.logInfo("Installing pip using get-pip.py")
 
+	if i.manager.dryRun() {
+		getPipPath := filepath.Join(os.TempDir(), "get-pip.py")
+		i.manager.planStep(&PlanStep{Action: PlanDownload, URL: getPipURL, Path: getPipPath})
+		_, err := i.manager.runPlanned(&Command{Name: pythonPath, Args: []string{getPipPath}}, "install pip with get-pip.py")
+		return err
+	}
+
 	// Download get-pip.py
 	getPipPath, err := i.downloadGetPip()
 	if err != nil {
@@ -278,11 +314,11 @@ func (i *Installer) installUsingGetPip(pythonPath string) error {
 	defer os.Remove(getPipPath)
 
 	// Run get-pip.py
-	cmd := exec.CommandContext(i.manager.ctx, pythonPath, getPipPath)
-	output, err := cmd.CombinedOutput()
+	cmd := &Command{Name: pythonPath, Args: []string{getPipPath}}
+	result, err := i.manager.runCommand(cmd)
 
 	if err != nil {
-		i.manager.logError("get-pip.py failed: %v, output: %s", err, string(output))
+		i.manager.logError("get-pip.py failed: %v, output: %s", err, result.Output)
 		return fmt.Errorf("get-pip.py execution failed: %w", err)
 	}
 
@@ -302,11 +338,20 @@ func (i *Installer) downloadGetPip() (string, error) {
 	defer tmpFile.Close()
 
 	// Download get-pip.py
+	timeout := 30 * time.Second
+	if configured := i.manager.currentConfig().Timeout; configured > 0 {
+		timeout = configured
+	}
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout: timeout,
 	}
 
-	resp, err := client.Get("https://bootstrap.pypa.io/get-pip.py")
+	req, err := http.NewRequestWithContext(i.manager.context(), http.MethodGet, getPipURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -334,23 +379,14 @@ func (m *Manager) GetVersion() (string, error) {
 		return "", ErrPipNotInstalled
 	}
 
-	var cmd *exec.Cmd
-	if strings.Contains(pipPath, " ") {
-		// Handle commands like "python -m pip"
-		parts := strings.Fields(pipPath)
-		args := append(parts[1:], "--version")
-		cmd = exec.CommandContext(m.ctx, parts[0], args...)
-	} else {
-		cmd = exec.CommandContext(m.ctx, pipPath, "--version")
-	}
-
-	output, err := cmd.Output()
+	cmd := m.pipCommand(pipPath, "--version")
+	result, err := m.runCommand(cmd)
 	if err != nil {
-		return "", m.createPipError(cmd.String(), string(output), -1, err)
+		return "", m.createPipError(cmd.String(), result.Stdout, -1, err)
 	}
 
 	// Parse version from output (e.g., "pip 21.3.1 from ...")
-	versionStr := string(output)
+	versionStr := result.Stdout
 	parts := strings.Fields(versionStr)
 	if len(parts) >= 2 {
 		return parts[1], nil
@@ -0,0 +1,265 @@
+package pip
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Interpreter sources reported by DiscoverInterpreters
+const (
+	InterpreterSourcePath   = "path"
+	InterpreterSourcePyenv  = "pyenv"
+	InterpreterSourceAsdf   = "asdf"
+	InterpreterSourceSystem = "system"
+)
+
+// Interpreter describes a Python interpreter found on the machine
+type Interpreter struct {
+	Path           string `json:"path"`
+	Executable     string `json:"executable"`     // sys.executable, with shims resolved
+	Version        string `json:"version"`        // e.g. "3.11.4"
+	Implementation string `json:"implementation"` // e.g. "CPython", "PyPy"
+	Architecture   string `json:"architecture"`   // e.g. "x86_64", "arm64"
+	Source         string `json:"source"`         // where it was found, e.g. "path" or "pyenv"
+
+	manager *Manager // runs Inspect
+}
+
+// interpreterProbeScript prints the details DiscoverInterpreters reports
+const interpreterProbeScript = `import json, platform, sys; ` +
+	`print(json.dumps({"version": platform.python_version(), ` +
+	`"implementation": platform.python_implementation(), ` +
+	`"architecture": platform.machine(), "executable": sys.executable}))`
+
+var (
+	// interpreterNameRegex matches python, python3, python3.12, pypy3 and similar
+	interpreterNameRegex = regexp.MustCompile(`^(python|pypy)([0-9]+(\.[0-9]+)?)?$`)
+)
+
+// interpreterDir is a directory searched for interpreters
+type interpreterDir struct {
+	path   string
+	source string
+}
+
+// DiscoverInterpreters lists every Python interpreter found on PATH, in
+// pyenv and asdf installations and shims, and in common system locations
+// such as /usr/local and /opt. Interpreters are listed in discovery order,
+// PATH first, and each is reported once even when reachable by several paths.
+func (m *Manager) DiscoverInterpreters() ([]*Interpreter, error) {
+	m.logDebug("Discovering Python interpreters")
+
+	var interpreters []*Interpreter
+	seenPaths := make(map[string]bool)
+	seenExecutables := make(map[string]bool)
+
+	for _, dir := range interpreterSearchDirs() {
+		for _, path := range interpreterCandidates(dir.path) {
+			resolved := path
+			if real, err := filepath.EvalSymlinks(path); err == nil {
+				resolved = real
+			}
+			if seenPaths[resolved] {
+				continue
+			}
+			seenPaths[resolved] = true
+
+			interpreter, err := m.probeInterpreter(path)
+			if err != nil {
+				m.logDebug("Skipping interpreter %s: %v", path, err)
+				continue
+			}
+
+			executable := interpreter.Executable
+			if real, err := filepath.EvalSymlinks(executable); err == nil {
+				executable = real
+			}
+			if executable != "" && seenExecutables[executable] {
+				continue
+			}
+			seenExecutables[executable] = true
+
+			interpreter.Source = dir.source
+			interpreters = append(interpreters, interpreter)
+		}
+	}
+
+	m.mu.Lock()
+	m.interpreters = interpreters
+	m.discovered = true
+	m.mu.Unlock()
+
+	return interpreters, nil
+}
+
+// FindInterpreter returns the newest discovered interpreter whose version
+// satisfies constraint, a PEP 440 specifier list such as ">=3.10,<3.13" or a
+// bare version such as "3.11". Discovery results are cached per manager;
+// call DiscoverInterpreters to refresh them.
+func (m *Manager) FindInterpreter(constraint string) (*Interpreter, error) {
+	specifiers, err := ParseSpecifierSet(constraint)
+	if err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidConfig),
+			Message: fmt.Sprintf("invalid Python version constraint: %v", err),
+		}
+	}
+
+	m.mu.RLock()
+	interpreters, discovered := m.interpreters, m.discovered
+	m.mu.RUnlock()
+
+	if !discovered {
+		if interpreters, err = m.DiscoverInterpreters(); err != nil {
+			return nil, err
+		}
+	}
+
+	var best *Interpreter
+	var bestVersion *Version
+	for _, interpreter := range interpreters {
+		version, err := ParseVersion(interpreter.Version)
+		if err != nil || !specifiers.Contains(version) {
+			continue
+		}
+		if best == nil || version.Compare(bestVersion) > 0 {
+			best, bestVersion = interpreter, version
+		}
+	}
+
+	if best == nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypePythonNotFound),
+			Message: fmt.Sprintf("no Python interpreter matches %q", constraint),
+		}
+	}
+
+	m.logDebug("Selected Python %s at %s for %q", best.Version, best.Path, constraint)
+	return best, nil
+}
+
+// probeInterpreter runs an interpreter once to read its version and platform
+func (m *Manager) probeInterpreter(path string) (*Interpreter, error) {
+	result, err := m.runCommand(&Command{Name: path, Args: []string{"-c", interpreterProbeScript}})
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		Version        string `json:"version"`
+		Implementation string `json:"implementation"`
+		Architecture   string `json:"architecture"`
+		Executable     string `json:"executable"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(result.Stdout)), &info); err != nil {
+		return nil, fmt.Errorf("unexpected interpreter output: %w", err)
+	}
+
+	return &Interpreter{
+		manager:        m,
+		Path:           path,
+		Executable:     info.Executable,
+		Version:        info.Version,
+		Implementation: info.Implementation,
+		Architecture:   info.Architecture,
+	}, nil
+}
+
+// interpreterSearchDirs returns the directories searched for interpreters,
+// in priority order
+func interpreterSearchDirs() []interpreterDir {
+	var dirs []interpreterDir
+	add := func(source string, patterns ...string) {
+		for _, pattern := range patterns {
+			matches, _ := filepath.Glob(pattern)
+			for _, match := range matches {
+				dirs = append(dirs, interpreterDir{path: match, source: source})
+			}
+		}
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir != "" {
+			dirs = append(dirs, interpreterDir{path: dir, source: InterpreterSourcePath})
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+
+	pyenvRoot := os.Getenv("PYENV_ROOT")
+	if pyenvRoot == "" && home != "" {
+		pyenvRoot = filepath.Join(home, ".pyenv")
+	}
+	if pyenvRoot != "" {
+		if runtime.GOOS == "windows" {
+			add(InterpreterSourcePyenv, filepath.Join(pyenvRoot, "pyenv-win", "versions", "*"))
+		}
+		add(InterpreterSourcePyenv,
+			filepath.Join(pyenvRoot, "versions", "*", "bin"),
+			filepath.Join(pyenvRoot, "shims"))
+	}
+
+	asdfDir := os.Getenv("ASDF_DATA_DIR")
+	if asdfDir == "" && home != "" {
+		asdfDir = filepath.Join(home, ".asdf")
+	}
+	if asdfDir != "" {
+		add(InterpreterSourceAsdf,
+			filepath.Join(asdfDir, "installs", "python", "*", "bin"),
+			filepath.Join(asdfDir, "shims"))
+	}
+
+	if runtime.GOOS == "windows" {
+		add(InterpreterSourceSystem,
+			filepath.Join(os.Getenv("LOCALAPPDATA"), "Programs", "Python", "Python*"),
+			filepath.Join(os.Getenv("ProgramFiles"), "Python*"),
+			`C:\Python*`)
+	} else {
+		add(InterpreterSourceSystem,
+			"/usr/local/bin",
+			"/usr/bin",
+			"/opt/homebrew/bin",
+			"/opt/*/bin",
+			"/Library/Frameworks/Python.framework/Versions/*/bin")
+	}
+
+	return dirs
+}
+
+// interpreterCandidates returns the interpreter executables in dir
+func interpreterCandidates(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if runtime.GOOS == "windows" {
+			if !strings.EqualFold(filepath.Ext(name), ".exe") {
+				continue
+			}
+			name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+		}
+		if !interpreterNameRegex.MatchString(name) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+		candidates = append(candidates, path)
+	}
+	return candidates
+}
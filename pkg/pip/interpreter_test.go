@@ -0,0 +1,198 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// fakeInterpreterEnv creates interpreter executables in a temporary PATH and
+// pyenv root and scripts their probe output
+func fakeInterpreterEnv(t *testing.T) (*FakeRunner, map[string]string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("interpreter discovery test uses Unix executable names")
+	}
+
+	root := t.TempDir()
+	binDir := filepath.Join(root, "bin")
+	pyenvBin := filepath.Join(root, "pyenv", "versions", "3.12.1", "bin")
+	for _, dir := range []string{binDir, pyenvBin} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths := map[string]string{
+		"python3":     filepath.Join(binDir, "python3"),
+		"python3.11":  filepath.Join(binDir, "python3.11"),
+		"pyenv3.12":   filepath.Join(pyenvBin, "python3.12"),
+		"pyenv3":      filepath.Join(pyenvBin, "python3"),
+		"prerelease":  filepath.Join(binDir, "python3.13"),
+		"unparseable": filepath.Join(binDir, "pypy3"),
+	}
+	for _, path := range paths {
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Symlinks, non-executables and helper scripts are not separate interpreters
+	paths["python"] = filepath.Join(binDir, "python")
+	if err := os.Symlink(paths["python3"], paths["python"]); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(binDir, "python2"), nil, 0644)
+	os.WriteFile(filepath.Join(binDir, "python3-config"), nil, 0755)
+
+	t.Setenv("PATH", binDir)
+	t.Setenv("PYENV_ROOT", filepath.Join(root, "pyenv"))
+	t.Setenv("ASDF_DATA_DIR", filepath.Join(root, "asdf"))
+
+	runner := NewFakeRunner()
+	probe := func(key, version, executable string) {
+		runner.On(paths[key], "-c", "*").Respond(fmt.Sprintf(
+			`{"version": %q, "implementation": "CPython", "architecture": "x86_64", "executable": %q}`,
+			version, executable), "", 0)
+	}
+	probe("python", "3.10.12", paths["python3"])
+	probe("python3", "3.10.12", paths["python3"])
+	probe("python3.11", "3.11.4", paths["python3.11"])
+	probe("pyenv3.12", "3.12.1", paths["pyenv3.12"])
+	probe("pyenv3", "3.12.1", paths["pyenv3.12"]) // same installation
+	probe("prerelease", "3.13.0rc1", paths["prerelease"])
+	runner.On(paths["unparseable"], "-c", "*").Respond("not json", "", 0)
+	runner.On("...").Fail(fmt.Errorf("not a test interpreter"))
+
+	return runner, paths
+}
+
+func TestDiscoverInterpreters(t *testing.T) {
+	runner, paths := fakeInterpreterEnv(t)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	interpreters, err := manager.DiscoverInterpreters()
+	if err != nil {
+		t.Fatalf("DiscoverInterpreters() error: %v", err)
+	}
+
+	found := make(map[string]*Interpreter)
+	for _, interpreter := range interpreters {
+		found[interpreter.Path] = interpreter
+	}
+
+	// The first path found for each installation is reported
+	for _, key := range []string{"python", "python3.11", "pyenv3", "prerelease"} {
+		if found[paths[key]] == nil {
+			t.Errorf("interpreter %s not discovered", paths[key])
+		}
+	}
+	for _, key := range []string{"python3", "pyenv3.12", "unparseable"} {
+		if found[paths[key]] != nil {
+			t.Errorf("duplicate or broken interpreter %s should be skipped", paths[key])
+		}
+	}
+
+	python := found[paths["python"]]
+	if python != nil && (python.Version != "3.10.12" || python.Implementation != "CPython" ||
+		python.Architecture != "x86_64" || python.Source != InterpreterSourcePath) {
+		t.Errorf("python = %+v", python)
+	}
+	if pyenv := found[paths["pyenv3"]]; pyenv != nil && pyenv.Source != InterpreterSourcePyenv {
+		t.Errorf("pyenv interpreter source = %q", pyenv.Source)
+	}
+}
+
+func TestFindInterpreter(t *testing.T) {
+	runner, paths := fakeInterpreterEnv(t)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{">=3.10,<3.13", paths["pyenv3"]},
+		{"3.11", paths["python3.11"]},
+		{"<3.11", paths["python"]},
+		{">=3.13.0rc1", paths["prerelease"]},
+		{"", paths["pyenv3"]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			interpreter, err := manager.FindInterpreter(tt.constraint)
+			if err != nil {
+				t.Fatalf("FindInterpreter(%q) error: %v", tt.constraint, err)
+			}
+			if interpreter.Path != tt.want {
+				t.Errorf("FindInterpreter(%q) = %s, want %s", tt.constraint, interpreter.Path, tt.want)
+			}
+		})
+	}
+
+	if _, err := manager.FindInterpreter(">=4"); !IsErrorType(err, ErrorTypePythonNotFound) {
+		t.Errorf("FindInterpreter(>=4) error = %v, want python_not_found", err)
+	}
+	if _, err := manager.FindInterpreter(">=x"); !IsErrorType(err, ErrorTypeInvalidConfig) {
+		t.Errorf("FindInterpreter(>=x) error = %v, want invalid_config", err)
+	}
+}
+
+func TestPythonVersionConfig(t *testing.T) {
+	runner, paths := fakeInterpreterEnv(t)
+
+	manager := NewManager(&Config{PythonVersion: "~=3.11.0"})
+	manager.SetCommandRunner(runner)
+
+	python, err := manager.findPythonExecutable()
+	if err != nil {
+		t.Fatalf("findPythonExecutable() error: %v", err)
+	}
+	if python != paths["python3.11"] {
+		t.Errorf("findPythonExecutable() = %s, want %s", python, paths["python3.11"])
+	}
+}
+
+func TestFindInterpreterCachesEmptyDiscovery(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interpreter discovery test uses Unix executable names")
+	}
+
+	binDir := t.TempDir()
+	broken := filepath.Join(binDir, "python3")
+	if err := os.WriteFile(broken, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+	t.Setenv("PYENV_ROOT", filepath.Join(binDir, "pyenv"))
+	t.Setenv("ASDF_DATA_DIR", filepath.Join(binDir, "asdf"))
+
+	runner := NewFakeRunner()
+	runner.On("...").Fail(fmt.Errorf("not a test interpreter"))
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	for i := 0; i < 2; i++ {
+		if _, err := manager.FindInterpreter(""); !IsErrorType(err, ErrorTypePythonNotFound) {
+			t.Fatalf("FindInterpreter() error = %v, want python_not_found", err)
+		}
+	}
+
+	probes := 0
+	for _, call := range runner.Calls() {
+		if call.Name == broken {
+			probes++
+		}
+	}
+	if probes != 1 {
+		t.Errorf("%s probed %d times, want 1", broken, probes)
+	}
+}
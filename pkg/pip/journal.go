@@ -0,0 +1,352 @@
+package pip
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalEntry records a single subprocess run by the SDK
+type JournalEntry struct {
+	Argv        []string          `json:"argv"`
+	Env         map[string]string `json:"env,omitempty"`         // variables added or changed relative to the parent, redacted
+	EnvRemoved  []string          `json:"env_removed,omitempty"` // parent variables not passed to the command
+	Dir         string            `json:"dir,omitempty"`
+	Venv        string            `json:"venv,omitempty"`
+	Host        string            `json:"host,omitempty"`
+	Packages    []string          `json:"packages,omitempty"` // normalized names the command operated on
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	ExitCode    int               `json:"exit_code"`
+	StdoutBytes int               `json:"stdout_bytes"`
+	StderrBytes int               `json:"stderr_bytes"`
+	ErrorType   ErrorType         `json:"error_type,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// JournalQuery selects journal entries. Zero fields match everything.
+type JournalQuery struct {
+	Package string    // entries that operated on this package
+	Since   time.Time // entries started at or after Since
+	Until   time.Time // entries started before Until
+}
+
+// Journal is an append-only JSONL log of every subprocess run by a Manager.
+// It is safe for concurrent use, and several processes may append to the
+// same file.
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJournal creates a journal that appends to the file at path
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Path returns the journal file path
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Record appends an entry to the journal
+func (j *Journal) Record(entry *JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if dir := filepath.Dir(j.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create journal directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	// A single write keeps concurrent appenders from interleaving lines
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return file.Close()
+}
+
+// Query returns the entries matching query in the order they were recorded
+func (j *Journal) Query(query *JournalQuery) ([]*JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return ReadJournal(j.path, query)
+}
+
+// ReadJournal reads the entries matching query from a journal file.
+// A truncated final line, left by a process that died mid-write, is ignored.
+func ReadJournal(path string, query *JournalQuery) ([]*JournalEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	if query == nil {
+		query = &JournalQuery{}
+	}
+
+	var entries []*JournalEntry
+	reader := bufio.NewReader(file)
+	for lineNum := 1; ; lineNum++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, fmt.Errorf("failed to read journal: %w", readErr)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var entry JournalEntry
+			if err := json.Unmarshal(trimmed, &entry); err != nil {
+				if readErr == io.EOF {
+					break
+				}
+				return nil, fmt.Errorf("invalid journal entry on line %d: %w", lineNum, err)
+			}
+			if query.matches(&entry) {
+				entries = append(entries, &entry)
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// matches reports whether entry is selected by the query
+func (q *JournalQuery) matches(entry *JournalEntry) bool {
+	if !q.Since.IsZero() && entry.Start.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !entry.Start.Before(q.Until) {
+		return false
+	}
+	if q.Package != "" && !containsString(entry.Packages, normalizePackageName(q.Package)) {
+		return false
+	}
+	return true
+}
+
+// SetJournal sets the journal that records every subprocess; nil disables it
+func (m *Manager) SetJournal(journal *Journal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.journal = journal
+}
+
+// GetJournal returns the configured journal, or nil
+func (m *Manager) GetJournal() *Journal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.journal
+}
+
+// recordJournal writes a journal entry for a finished command.
+// Journal failures are logged and never fail the command itself.
+func (m *Manager) recordJournal(cmd *Command, result *CommandResult, err error, start, end time.Time) {
+	journal := m.GetJournal()
+	if journal == nil {
+		return
+	}
+
+	entry := &JournalEntry{
+		Argv:        redactArgs(cmd.Argv()),
+		Dir:         cmd.Dir,
+		Packages:    commandPackages(cmd.Argv()),
+		Start:       start,
+		End:         end,
+		ExitCode:    result.ExitCode,
+		StdoutBytes: len(result.Stdout),
+		StderrBytes: len(result.Stderr),
+	}
+	entry.Env, entry.EnvRemoved = environmentDiff(os.Environ(), cmd.Env)
+	entry.Venv = envValue(cmd.Env, "VIRTUAL_ENV")
+	entry.Host, _ = os.Hostname()
+	if entry.Dir == "" {
+		entry.Dir, _ = os.Getwd()
+	}
+	if err != nil {
+		entry.ErrorType = commandErrorType(result.Output, err)
+		entry.Error = redactValue(err.Error())
+	}
+
+	if err := journal.Record(entry); err != nil {
+		m.logWarn("Failed to record command in journal: %v", err)
+	}
+}
+
+var (
+	// sensitiveEnvRegex matches variable names whose values are never journaled
+	sensitiveEnvRegex = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|CREDENTIAL|API_?KEY|AUTH)`)
+
+	// urlCredentialsRegex matches the userinfo part of URLs such as index URLs
+	urlCredentialsRegex = regexp.MustCompile(`://[^/\s@]+@`)
+)
+
+// redactedValue replaces sensitive values in the journal
+const redactedValue = "[REDACTED]"
+
+// redactValue removes credentials embedded in URLs
+func redactValue(value string) string {
+	return urlCredentialsRegex.ReplaceAllString(value, "://"+redactedValue+"@")
+}
+
+// redactArgs returns args with URL credentials removed
+func redactArgs(args []string) []string {
+	result := make([]string, len(args))
+	for i, arg := range args {
+		result[i] = redactValue(arg)
+	}
+	return result
+}
+
+// environmentDiff returns the variables env adds or changes relative to
+// parent, with sensitive values redacted, and the parent names env drops.
+// A nil env inherits the parent unchanged.
+func environmentDiff(parent, env []string) (map[string]string, []string) {
+	if env == nil {
+		return nil, nil
+	}
+
+	parentVars := envMap(parent)
+	envVars := envMap(env)
+
+	changed := make(map[string]string)
+	for key, value := range envVars {
+		if parentValue, ok := parentVars[key]; ok && parentValue == value {
+			continue
+		}
+		if sensitiveEnvRegex.MatchString(key) {
+			changed[key] = redactedValue
+		} else {
+			changed[key] = redactValue(value)
+		}
+	}
+
+	var removed []string
+	for key := range parentVars {
+		if _, ok := envVars[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+
+	if len(changed) == 0 {
+		changed = nil
+	}
+	return changed, removed
+}
+
+// envMap converts NAME=value entries to a map keyed by envKey
+func envMap(env []string) map[string]string {
+	result := make(map[string]string, len(env))
+	for _, entry := range env {
+		if idx := strings.Index(entry, "="); idx > 0 {
+			result[envKey(entry[:idx])] = entry[idx+1:]
+		}
+	}
+	return result
+}
+
+// envValue returns the value of name in env, or ""
+func envValue(env []string, name string) string {
+	return envMap(env)[envKey(name)]
+}
+
+// pipValueOptions are pip options that take a separate value argument
+var pipValueOptions = map[string]bool{
+	"-r": true, "--requirement": true, "-c": true, "--constraint": true, "-e": true, "--editable": true,
+	"-i": true, "--index-url": true, "--extra-index-url": true, "-f": true, "--find-links": true,
+	"--trusted-host": true, "--keyring-provider": true, "--cache-dir": true, "-t": true, "--target": true,
+	"--prefix": true, "--root": true, "--src": true, "--path": true, "-d": true, "--dest": true,
+	"-w": true, "--wheel-dir": true, "--report": true, "--platform": true,
+	"--python-version": true, "--implementation": true, "--abi": true,
+	"--upgrade-strategy": true, "--no-binary": true, "--only-binary": true, "--exclude": true,
+	"-C": true, "--config-settings": true, "--global-option": true, "--build-option": true,
+	"--progress-bar": true, "--log": true, "--log-file": true, "--timeout": true, "--retries": true,
+	"--proxy": true, "--cert": true, "--client-cert": true, "--format": true, "--python": true,
+	"--exists-action": true, "--use-feature": true, "--use-deprecated": true,
+}
+
+// pipPackageCommands are pip subcommands whose positional arguments name packages
+var pipPackageCommands = map[string]bool{
+	"install": true, "uninstall": true, "download": true, "wheel": true, "show": true,
+}
+
+// commandPackages returns the normalized package names named by a pip argv,
+// for either "pip <command> ..." or "python -m pip <command> ..."
+func commandPackages(argv []string) []string {
+	start := -1
+	for i, arg := range argv {
+		if i > 0 && arg == "pip" && argv[i-1] == "-m" {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 && len(argv) > 0 && strings.HasPrefix(filepath.Base(argv[0]), "pip") {
+		start = 1
+	}
+	if start < 0 || start >= len(argv) {
+		return nil
+	}
+
+	args := argv[start:]
+	var command string
+	var packages []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if pipValueOptions[arg] {
+				i++
+			}
+			continue
+		}
+		if command == "" {
+			command = arg
+			if !pipPackageCommands[command] {
+				return nil
+			}
+			continue
+		}
+		if name := requirementName(arg); name != "" && !containsString(packages, name) {
+			packages = append(packages, name)
+		}
+	}
+	return packages
+}
+
+// requirementName returns the normalized project name of a requirement
+// argument, or "" for local paths and URLs
+func requirementName(arg string) string {
+	if name, _ := splitDistFilename(filepath.Base(arg)); name != "" {
+		return normalizePackageName(name)
+	}
+	if strings.Contains(arg, "://") || strings.ContainsAny(arg, `/\`) || strings.HasPrefix(arg, ".") {
+		return ""
+	}
+	return normalizePackageName(requirementNameRegex.FindString(arg))
+}
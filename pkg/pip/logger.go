@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -42,6 +43,7 @@ func (l LogLevel) String() string {
 
 // Logger represents a custom logger for pip operations
 type Logger struct {
+	mu         sync.Mutex // serializes writes to output
 	level      LogLevel
 	output     io.Writer
 	prefix     string
@@ -171,7 +173,9 @@ func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
 
 	// Write to output
 	if l.output != nil {
+		l.mu.Lock()
 		l.output.Write([]byte(logEntry))
+		l.mu.Unlock()
 	}
 }
 
@@ -272,41 +276,54 @@ func ParseLogLevel(level string) (LogLevel, error) {
 
 // Update Manager to use the new logger
 func (m *Manager) SetCustomLogger(logger *Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.customLogger = logger
 }
 
+// loggers returns the custom and standard loggers under the read lock
+func (m *Manager) loggers() (*Logger, *log.Logger) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.customLogger, m.logger
+}
+
 // logInfo logs an info message using custom logger if available
 func (m *Manager) logInfo(format string, args ...interface{}) {
-	if m.customLogger != nil {
-		m.customLogger.Info(format, args...)
-	} else if m.logger != nil {
-		m.logger.Printf("[INFO] "+format, args...)
+	customLogger, logger := m.loggers()
+	if customLogger != nil {
+		customLogger.Info(format, args...)
+	} else if logger != nil {
+		logger.Printf("[INFO] "+format, args...)
 	}
 }
 
 // logError logs an error message using custom logger if available
 func (m *Manager) logError(format string, args ...interface{}) {
-	if m.customLogger != nil {
-		m.customLogger.Error(format, args...)
-	} else if m.logger != nil {
-		m.logger.Printf("[ERROR] "+format, args...)
+	customLogger, logger := m.loggers()
+	if customLogger != nil {
+		customLogger.Error(format, args...)
+	} else if logger != nil {
+		logger.Printf("[ERROR] "+format, args...)
 	}
 }
 
 // logDebug logs a debug message using custom logger if available
 func (m *Manager) logDebug(format string, args ...interface{}) {
-	if m.customLogger != nil {
-		m.customLogger.Debug(format, args...)
-	} else if m.logger != nil && m.config.LogLevel == "DEBUG" {
-		m.logger.Printf("[DEBUG] "+format, args...)
+	customLogger, logger := m.loggers()
+	if customLogger != nil {
+		customLogger.Debug(format, args...)
+	} else if logger != nil && m.currentConfig().LogLevel == "DEBUG" {
+		logger.Printf("[DEBUG] "+format, args...)
 	}
 }
 
 // logWarn logs a warning message using custom logger if available
 func (m *Manager) logWarn(format string, args ...interface{}) {
-	if m.customLogger != nil {
-		m.customLogger.Warn(format, args...)
-	} else if m.logger != nil {
-		m.logger.Printf("[WARN] "+format, args...)
+	customLogger, logger := m.loggers()
+	if customLogger != nil {
+		customLogger.Warn(format, args...)
+	} else if logger != nil {
+		logger.Printf("[WARN] "+format, args...)
 	}
 }
@@ -2,19 +2,35 @@ package pip
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 )
 
-// Manager implements the PipManager interface
+// Manager implements the PipManager interface.
+//
+// A Manager is safe for concurrent use. The Config passed to it should not be
+// modified after the manager is shared between goroutines; use SetConfig or a
+// scoped manager from ForVenv or ForInterpreter instead.
 type Manager struct {
+	mu           sync.RWMutex
 	config       *Config
 	logger       *log.Logger
 	customLogger *Logger
+	runner       CommandRunner
+	progress     ProgressReporter
+	journal      *Journal
+	plan         *Plan                   // steps recorded in dry-run mode
+	interpreters []*Interpreter          // cached DiscoverInterpreters result
+	discovered   bool                    // interpreters holds a result, possibly empty
+	indexClients map[string]*IndexClient // cached IndexClient results, by index URL
 	ctx          context.Context
+	scoped       bool // created by ForVenv or ForInterpreter; configuration is fixed
 }
 
 // NewManager creates a new pip manager instance
@@ -28,6 +44,7 @@ func NewManager(config *Config) *Manager {
 	return &Manager{
 		config: config,
 		logger: logger,
+		runner: NewExecRunner(),
 		ctx:    context.Background(),
 	}
 }
@@ -44,6 +61,7 @@ func DefaultConfig() *Config {
 	return &Config{
 		Timeout:      30 * time.Second,
 		Retries:      3,
+		RetryDelay:   time.Second,
 		LogLevel:     "INFO",
 		CacheDir:     "",
 		Environment:  make(map[string]string),
@@ -65,26 +83,53 @@ func GetOSType() OSType {
 	}
 }
 
-// SetConfig updates the manager configuration
+// SetConfig updates the manager configuration. Scoped managers keep their
+// configuration and ignore this call.
 func (m *Manager) SetConfig(config *Config) {
+	if m.scoped {
+		m.logWarn("SetConfig ignored on scoped manager")
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.config = config
+	m.indexClients = nil
 }
 
 // GetConfig returns the current configuration
 func (m *Manager) GetConfig() *Config {
-	return m.config
+	return m.currentConfig()
 }
 
 // SetLogger sets a custom logger
 func (m *Manager) SetLogger(logger *log.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.logger = logger
 }
 
 // SetContext sets the context for operations
 func (m *Manager) SetContext(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.ctx = ctx
 }
 
+// currentConfig returns the configuration under the read lock
+func (m *Manager) currentConfig() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
+// context returns the operation context under the read lock
+func (m *Manager) context() context.Context {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ctx
+}
+
 // validatePackageSpec validates a package specification
 func (m *Manager) validatePackageSpec(pkg *PackageSpec) error {
 	if pkg == nil {
@@ -116,5 +161,26 @@ func (m *Manager) createPipError(command string, output string, exitCode int, er
 		pipErr.Message = fmt.Sprintf("pip command failed with exit code %d", exitCode)
 	}
 
+	pipErr.Type = string(commandErrorType(output, err))
+
 	return pipErr
 }
+
+// commandErrorType classifies a failed command so callers and the retry
+// loop can tell transient failures apart from deterministic ones
+func commandErrorType(output string, err error) ErrorType {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTypeTimeout
+	}
+	var pipErr *PipError
+	if errors.As(err, &pipErr) {
+		return ErrorType(pipErr.Type)
+	}
+	if strings.Contains(output, "No module named pip") {
+		return ErrorTypePipNotInstalled
+	}
+	if errorType := ClassifyOutput(output); errorType != "" {
+		return errorType
+	}
+	return ErrorTypeCommandFailed
+}
@@ -0,0 +1,225 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MissingRequirement is a requirement no file in the wheelhouse satisfies
+type MissingRequirement struct {
+	Name      string   `json:"name"`
+	Specifier string   `json:"specifier,omitempty"`
+	Available []string `json:"available,omitempty"` // installable versions of the package in the wheelhouse
+
+	// Unsupported lists wheels of the package built for another platform,
+	// ABI or Python version than the target interpreter
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// String returns the requirement, e.g. "requests>=2.0"
+func (r *MissingRequirement) String() string {
+	return r.Name + r.Specifier
+}
+
+// WheelhouseError reports requirements that cannot be installed in offline
+// mode because the wheelhouse has no matching file
+type WheelhouseError struct {
+	Dirs    []string              `json:"dirs"`
+	Missing []*MissingRequirement `json:"missing"`
+}
+
+func (e *WheelhouseError) Error() string {
+	parts := make([]string, len(e.Missing))
+	for i, missing := range e.Missing {
+		parts[i] = missing.String()
+		if len(missing.Available) > 0 {
+			parts[i] += " (have " + strings.Join(missing.Available, ", ") + ")"
+		}
+		if len(missing.Unsupported) > 0 {
+			parts[i] += " (incompatible wheels: " + strings.Join(missing.Unsupported, ", ") + ")"
+		}
+	}
+	return fmt.Sprintf("offline mode: not available in wheelhouse %s: %s",
+		strings.Join(e.Dirs, ", "), strings.Join(parts, "; "))
+}
+
+// Wheelhouse lists the distribution files in Config.WheelhouseDirs by
+// normalized package name. Files that are not wheels or source
+// distributions are ignored.
+func (m *Manager) Wheelhouse() (map[string][]*Artifact, error) {
+	config := m.currentConfig()
+
+	contents := make(map[string][]*Artifact)
+	for _, dir := range config.WheelhouseDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, &PipError{
+				Type:    string(ErrorTypeInvalidPath),
+				Message: fmt.Sprintf("cannot read wheelhouse %s: %v", dir, err),
+			}
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			artifact, err := ParseArtifactFilename(entry.Name())
+			if err != nil {
+				continue
+			}
+			artifact.Path = filepath.Join(dir, entry.Name())
+			key := normalizePackageName(artifact.Name)
+			contents[key] = append(contents[key], artifact)
+		}
+	}
+	return contents, nil
+}
+
+// checkWheelhouse verifies in offline mode that the wheelhouse can satisfy
+// every requested package and every requirement in the given requirement
+// files. Editable installs, direct references ("name @ url") and
+// requirements with environment markers are left to pip. Only the
+// requested requirements are checked, not their dependencies. Wheels count
+// only when the interpreter pip runs under supports one of their tags.
+func (m *Manager) checkWheelhouse(pkgs []*PackageSpec, requirementFiles []string) error {
+	config := m.currentConfig()
+	if !config.Offline {
+		return nil
+	}
+	if len(config.WheelhouseDirs) == 0 {
+		return &PipError{
+			Type:    string(ErrorTypeInvalidConfig),
+			Message: "offline mode requires at least one wheelhouse directory",
+		}
+	}
+
+	var requirements []*fileRequirement
+	for _, pkg := range pkgs {
+		if !pkg.Editable {
+			requirements = append(requirements, &fileRequirement{name: normalizePackageName(pkg.Name), specifier: pkg.Version})
+		}
+	}
+	for _, path := range requirementFiles {
+		fileRequirements, err := readRequirements(path, make(map[string]bool))
+		if err != nil {
+			return &PipError{
+				Type:    string(ErrorTypeFileNotFound),
+				Message: fmt.Sprintf("failed to read requirements file: %v", err),
+			}
+		}
+		requirements = append(requirements, fileRequirements...)
+	}
+
+	contents, err := m.Wheelhouse()
+	if err != nil {
+		return err
+	}
+
+	var supported []string
+	inspected := false
+	var missing []*MissingRequirement
+	seen := make(map[string]bool)
+	for _, req := range requirements {
+		key := req.name + req.specifier
+		if req.direct || req.marker != "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if !inspected {
+			if supported, err = m.supportedTags(); err != nil {
+				return err
+			}
+			inspected = true
+		}
+		if result := wheelhouseSatisfies(contents[req.name], req.specifier, supported); result != nil {
+			result.Name = req.name
+			missing = append(missing, result)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &WheelhouseError{Dirs: config.WheelhouseDirs, Missing: missing}
+	}
+	return nil
+}
+
+// supportedTags returns the wheel tags of the interpreter pip runs under
+func (m *Manager) supportedTags() ([]string, error) {
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return nil, ErrPipNotInstalled
+	}
+
+	python := strings.TrimSuffix(pipPath, pipModuleSuffix)
+	if python == pipPath {
+		if python, err = m.findPythonExecutable(); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := m.InspectInterpreter(python)
+	if err != nil {
+		return nil, err
+	}
+	return info.Tags, nil
+}
+
+// wheelhouseSatisfies returns nil when one of the files matches the
+// specifier and supports one of the given tags, and otherwise the versions
+// that are available. Pre-releases match only when no final release does,
+// as with pip. Source distributions match any interpreter.
+func wheelhouseSatisfies(files []*Artifact, specifier string, supported []string) *MissingRequirement {
+	specs, specErr := ParseSpecifierSet(specifier)
+
+	var available []*Version
+	var unsupported []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if !file.Supports(supported) {
+			unsupported = append(unsupported, file.Filename)
+			continue
+		}
+		version, err := ParseVersion(file.Version)
+		if err != nil || seen[version.String()] {
+			continue
+		}
+		seen[version.String()] = true
+		available = append(available, version)
+	}
+
+	if specErr == nil {
+		for _, version := range available {
+			if specs.Contains(version) {
+				return nil
+			}
+		}
+		for _, version := range available {
+			if version.IsPrerelease() && containsEach(specs, version) {
+				return nil
+			}
+		}
+	}
+
+	sort.Slice(available, func(i, j int) bool { return available[i].Compare(available[j]) < 0 })
+	sort.Strings(unsupported)
+	missing := &MissingRequirement{Specifier: specifier, Unsupported: unsupported}
+	for _, version := range available {
+		missing.Available = append(missing.Available, version.String())
+	}
+	return missing
+}
+
+// containsEach reports whether v satisfies every specifier, ignoring the
+// rule that excludes pre-releases
+func containsEach(specs SpecifierSet, v *Version) bool {
+	for _, spec := range specs {
+		if !spec.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package pip
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newWheelhouse creates a directory containing empty files with the given names
+func newWheelhouse(t *testing.T, files ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// newOfflineRunner returns a FakeRunner whose interpreter reports the tags
+// in fakeInspectOutput (CPython 3.11 on x86_64 Linux)
+func newOfflineRunner() *FakeRunner {
+	runner := NewFakeRunner()
+	runner.On("python", "-c", "*").Respond(fakeInspectOutput, "", 0)
+	return runner
+}
+
+// pipRuns counts the pip commands a runner received
+func pipRuns(runner *FakeRunner) int {
+	runs := 0
+	for _, cmd := range runner.Calls() {
+		if len(cmd.Args) >= 2 && cmd.Args[0] == "-m" && cmd.Args[1] == "pip" {
+			runs++
+		}
+	}
+	return runs
+}
+
+func TestOfflineGlobalOptions(t *testing.T) {
+	manager := NewManager(&Config{
+		Offline:        true,
+		WheelhouseDirs: []string{"/srv/wheels", "/opt/extra"},
+		DefaultIndex:   "https://pypi.org/simple/",
+	})
+
+	got := manager.applyGlobalOptions([]string{"install", "six", "--find-links", "/opt/extra"})
+	want := "install six --find-links /opt/extra --no-index --find-links /srv/wheels"
+	if strings.Join(got, " ") != want {
+		t.Errorf("applyGlobalOptions() = %s\nwant %s", strings.Join(got, " "), want)
+	}
+
+	if got := manager.applyGlobalOptions([]string{"show", "six"}); len(got) != 2 {
+		t.Errorf("non-network command got offline options: %v", got)
+	}
+}
+
+func TestOfflineInstallPackage(t *testing.T) {
+	wheelhouse := newWheelhouse(t, "six-1.16.0-py2.py3-none-any.whl", "PyYAML-6.0.1.tar.gz", "README.txt")
+
+	runner := newOfflineRunner()
+	runner.On("python", "-m", "pip", "install", "pyyaml>=6", "--no-index", "--find-links", wheelhouse).Respond(
+		"Successfully installed PyYAML-6.0.1\n", "", 0)
+
+	manager := NewManager(&Config{Offline: true, WheelhouseDirs: []string{wheelhouse}})
+	manager.SetCommandRunner(runner)
+
+	if err := manager.InstallPackage(&PackageSpec{Name: "pyyaml", Version: ">=6"}); err != nil {
+		t.Fatalf("InstallPackage() error: %v", err)
+	}
+	if runs := pipRuns(runner); runs != 1 {
+		t.Errorf("got %d pip runs, want 1", runs)
+	}
+}
+
+func TestOfflineMissingRequirements(t *testing.T) {
+	wheelhouse := newWheelhouse(t, "six-1.15.0-py2.py3-none-any.whl", "six-1.16.0-py2.py3-none-any.whl", "click-8.1.7-py3-none-any.whl")
+
+	requirements := filepath.Join(t.TempDir(), "requirements.txt")
+	content := "six>=1.17 \\\n    --hash=sha256:abc\n" +
+		"Click==8.1.7\n" +
+		"requests[socks]==2.31.0\n" +
+		"pywin32==306; sys_platform == 'win32'\n" +
+		"mylib @ file:///srv/src/mylib\n"
+	if err := os.WriteFile(requirements, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := newOfflineRunner()
+	manager := NewManager(&Config{Offline: true, WheelhouseDirs: []string{wheelhouse}})
+	manager.SetCommandRunner(runner)
+
+	err := manager.InstallRequirements(requirements)
+	if !IsErrorType(err, ErrorTypeNotInWheelhouse) {
+		t.Fatalf("error = %v, want not_in_wheelhouse", err)
+	}
+	if pipRuns(runner) != 0 {
+		t.Error("pip should not run when the wheelhouse is incomplete")
+	}
+
+	var wheelhouseErr *WheelhouseError
+	if !errors.As(err, &wheelhouseErr) {
+		t.Fatalf("error is %T, want *WheelhouseError", err)
+	}
+	var missing []string
+	for _, req := range wheelhouseErr.Missing {
+		missing = append(missing, req.String())
+	}
+	if strings.Join(missing, ",") != "six>=1.17,requests==2.31.0" {
+		t.Errorf("missing = %v", missing)
+	}
+	if available := wheelhouseErr.Missing[0].Available; strings.Join(available, ",") != "1.15.0,1.16.0" {
+		t.Errorf("available six versions = %v", available)
+	}
+	if !strings.Contains(err.Error(), "six>=1.17 (have 1.15.0, 1.16.0)") {
+		t.Errorf("Error() = %s", err)
+	}
+}
+
+func TestOfflineInstallPackagesBatch(t *testing.T) {
+	wheelhouse := newWheelhouse(t, "six-1.16.0-py2.py3-none-any.whl", "attrs-24.1.0b1-py3-none-any.whl")
+
+	runner := newOfflineRunner()
+	manager := NewManager(&Config{Offline: true, WheelhouseDirs: []string{wheelhouse}})
+	manager.SetCommandRunner(runner)
+
+	results, err := manager.InstallPackages([]*PackageSpec{
+		{Name: "six"},
+		{Name: "attrs"}, // only a pre-release is available, which pip accepts
+		{Name: "numpy", Version: "==1.26.4"},
+		{Name: "local", Editable: true},
+	}, nil)
+	if !IsErrorType(err, ErrorTypeNotInWheelhouse) {
+		t.Fatalf("error = %v, want not_in_wheelhouse", err)
+	}
+	if !strings.Contains(err.Error(), "numpy==1.26.4") || strings.Contains(err.Error(), "six") || strings.Contains(err.Error(), "attrs") {
+		t.Errorf("error = %v", err)
+	}
+	for _, result := range results {
+		if result.Success || result.Error == nil {
+			t.Errorf("result = %+v, want the batch rejected", result)
+		}
+	}
+	if pipRuns(runner) != 0 {
+		t.Error("pip should not run when the wheelhouse is incomplete")
+	}
+}
+
+func TestOfflineUnsupportedWheels(t *testing.T) {
+	wheelhouse := newWheelhouse(t,
+		"numpy-1.26.4-cp311-cp311-manylinux_2_17_aarch64.whl",
+		"numpy-1.26.4-cp312-cp312-manylinux_2_17_x86_64.whl",
+		"orjson-3.9.10-cp311-cp311-manylinux_2_17_x86_64.whl",
+		"simplejson-3.19.2.tar.gz")
+
+	runner := newOfflineRunner()
+	manager := NewManager(&Config{Offline: true, WheelhouseDirs: []string{wheelhouse}})
+	manager.SetCommandRunner(runner)
+
+	_, err := manager.InstallPackages([]*PackageSpec{
+		{Name: "numpy", Version: "==1.26.4"},
+		{Name: "orjson"},
+		{Name: "simplejson"}, // source distributions build on any platform
+	}, nil)
+
+	var wheelhouseErr *WheelhouseError
+	if !errors.As(err, &wheelhouseErr) {
+		t.Fatalf("error = %v, want *WheelhouseError", err)
+	}
+	if len(wheelhouseErr.Missing) != 1 || wheelhouseErr.Missing[0].Name != "numpy" {
+		t.Fatalf("missing = %v", wheelhouseErr.Missing)
+	}
+	missing := wheelhouseErr.Missing[0]
+	if len(missing.Available) != 0 || len(missing.Unsupported) != 2 {
+		t.Errorf("missing = %+v, want two unsupported wheels and nothing available", missing)
+	}
+	if !strings.Contains(err.Error(), "incompatible wheels: numpy-1.26.4-cp311-cp311-manylinux_2_17_aarch64.whl") {
+		t.Errorf("Error() = %s", err)
+	}
+	if pipRuns(runner) != 0 {
+		t.Error("pip should not run when the wheelhouse has no compatible wheel")
+	}
+}
+
+func TestOfflineConfigErrors(t *testing.T) {
+	manager := NewManager(&Config{Offline: true})
+	manager.SetCommandRunner(NewFakeRunner())
+	if err := manager.InstallPackage(&PackageSpec{Name: "six"}); !IsErrorType(err, ErrorTypeInvalidConfig) {
+		t.Errorf("no wheelhouse: error = %v, want invalid_config", err)
+	}
+
+	manager.SetConfig(&Config{Offline: true, WheelhouseDirs: []string{"/nonexistent/wheels"}})
+	if err := manager.InstallPackage(&PackageSpec{Name: "six"}); !IsErrorType(err, ErrorTypeInvalidPath) {
+		t.Errorf("missing wheelhouse: error = %v, want invalid_path", err)
+	}
+}
@@ -1,11 +1,9 @@
 package pip
 
 import (
-	"encoding/json"
-	"fmt"
-	"os/exec"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 // InstallPackage installs a Python package
@@ -13,26 +11,22 @@ func (m *Manager) InstallPackage(pkg *PackageSpec) error {
 	if err := m.validatePackageSpec(pkg); err != nil {
 		return err
 	}
+	if err := m.checkWheelhouse([]*PackageSpec{pkg}, nil); err != nil {
+		return err
+	}
 
 	m.logInfo("Installing package: %s", pkg.Name)
 
-	pipPath, err := m.findPipExecutable()
-	if err != nil {
-		return ErrPipNotInstalled
-	}
+	_, err := m.runInstallStream(pkg, m.buildInstallArgs(pkg), nil)
+	return err
+}
 
-	// Build command arguments
+// buildInstallArgs builds pip install arguments for a package specification
+func (m *Manager) buildInstallArgs(pkg *PackageSpec) []string {
 	args := []string{"install"}
 
 	// Add package specification
-	packageSpec := pkg.Name
-	if pkg.Version != "" {
-		packageSpec += pkg.Version
-	}
-	if len(pkg.Extras) > 0 {
-		packageSpec += "[" + strings.Join(pkg.Extras, ",") + "]"
-	}
-	args = append(args, packageSpec)
+	args = append(args, requirementString(pkg))
 
 	// Add options
 	if pkg.Upgrade {
@@ -48,17 +42,7 @@ func (m *Manager) InstallPackage(pkg *PackageSpec) error {
 		args = append(args, "--index-url", pkg.Index)
 	}
 
-	// Add extra options
-	for key, value := range pkg.Options {
-		if value == "" {
-			args = append(args, "--"+key)
-		} else {
-			args = append(args, "--"+key, value)
-		}
-	}
-
-	// Execute command
-	return m.executePipCommand(pipPath, args)
+	return appendSortedOptions(args, pkg.Options)
 }
 
 // UninstallPackage uninstalls a Python package
@@ -78,34 +62,99 @@ func (m *Manager) UninstallPackage(name string) error {
 	}
 
 	args := []string{"uninstall", "-y", name}
+	if m.planPipCommand(pipPath, args, "uninstall "+name) {
+		return nil
+	}
 	return m.executePipCommand(pipPath, args)
 }
 
 // ListPackages lists all installed packages
 func (m *Manager) ListPackages() ([]*Package, error) {
+	return m.ListPackagesWithOptions(nil)
+}
+
+// ListPackagesWithOptions lists installed packages matching opts
+func (m *Manager) ListPackagesWithOptions(opts *ListOptions) ([]*Package, error) {
 	m.logDebug("Listing installed packages")
 
+	args, err := buildListArgs(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	pipPath, err := m.findPipExecutable()
 	if err != nil {
 		return nil, ErrPipNotInstalled
 	}
 
-	args := []string{"list", "--format=json"}
 	output, err := m.executePipCommandWithOutput(pipPath, args)
 	if err != nil {
 		return nil, err
 	}
 
 	// Parse JSON output
-	var packages []*Package
-	if err := json.Unmarshal([]byte(output), &packages); err != nil {
+	packages, err := parseJSONPackages(output)
+	if err != nil {
 		// Fallback to parsing text format
 		return m.parseListOutput(output), nil
 	}
 
+	for _, pkg := range packages {
+		if pkg.EditableLocation != "" {
+			pkg.Editable = true
+		}
+	}
 	return packages, nil
 }
 
+// buildListArgs builds pip list arguments for the given options
+func buildListArgs(opts *ListOptions) ([]string, error) {
+	args := []string{"list", "--format=json"}
+	if opts == nil {
+		return args, nil
+	}
+
+	if opts.Editable && opts.ExcludeEditable {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidConfig),
+			Message: "Editable and ExcludeEditable cannot both be set",
+		}
+	}
+	if len(opts.Path) > 0 && (opts.User || opts.Local) {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidConfig),
+			Message: "Path cannot be combined with User or Local",
+		}
+	}
+
+	if opts.NotRequired {
+		args = append(args, "--not-required")
+	}
+	if opts.User {
+		args = append(args, "--user")
+	}
+	if opts.Local {
+		args = append(args, "--local")
+	}
+	if opts.Editable {
+		args = append(args, "--editable")
+	}
+	if opts.ExcludeEditable {
+		args = append(args, "--exclude-editable")
+	}
+	for _, name := range opts.Exclude {
+		args = append(args, "--exclude", name)
+	}
+	for _, path := range opts.Path {
+		args = append(args, "--path", path)
+	}
+	if opts.Verbose {
+		args = append(args, "--verbose")
+	}
+
+	return args, nil
+}
+
 // ShowPackage shows detailed information about a package
 func (m *Manager) ShowPackage(name string) (*PackageInfo, error) {
 	if name == "" {
@@ -131,24 +180,6 @@ func (m *Manager) ShowPackage(name string) (*PackageInfo, error) {
 	return m.parseShowOutput(output), nil
 }
 
-// SearchPackages searches for packages (Note: pip search was disabled, using alternative approach)
-func (m *Manager) SearchPackages(query string) ([]*SearchResult, error) {
-	if query == "" {
-		return nil, &PipError{
-			Type:    "invalid_package_spec",
-			Message: "search query cannot be empty",
-		}
-	}
-
-	m.logDebug("Searching packages: %s", query)
-
-	// Since pip search is disabled, we'll return an error with a helpful message
-	return nil, &PipError{
-		Type:    "feature_disabled",
-		Message: "pip search has been disabled. Please use https://pypi.org to search for packages",
-	}
-}
-
 // FreezePackages returns a list of installed packages with versions
 func (m *Manager) FreezePackages() ([]*Package, error) {
 	m.logDebug("Freezing packages")
@@ -175,42 +206,80 @@ func (m *Manager) executePipCommand(pipPath string, args []string) error {
 
 // executePipCommandWithOutput executes a pip command and returns output
 func (m *Manager) executePipCommandWithOutput(pipPath string, args []string) (string, error) {
-	var cmd *exec.Cmd
+	return m.executePipCommandStreaming(pipPath, args, nil, nil)
+}
 
-	if strings.Contains(pipPath, " ") {
-		// Handle commands like "python -m pip"
-		parts := strings.Fields(pipPath)
-		cmdArgs := append(parts[1:], args...)
-		cmd = exec.CommandContext(m.ctx, parts[0], cmdArgs...)
-	} else {
-		cmd = exec.CommandContext(m.ctx, pipPath, args...)
-	}
+// executePipCommandStreaming executes a pip command, sending each output line
+// to handler as it is produced, and returns the complete output. Transient
+// failures are retried up to Config.Retries times with exponential backoff;
+// restart, if set, is called before each retry so callers collecting lines
+// can drop those of the failed attempt.
+func (m *Manager) executePipCommandStreaming(pipPath string, args []string, handler OutputHandler, restart func()) (string, error) {
+	args = m.applyGlobalOptions(args)
+	retries := m.currentConfig().Retries
+	ctx := m.context()
+
+	for attempt := 0; ; attempt++ {
+		output, err := m.executePipCommandOnce(pipPath, args, handler)
+		if err == nil || attempt >= retries || !IsTransientError(err) || ctx.Err() != nil {
+			return output, err
+		}
 
-	// Set environment variables
-	if len(m.config.Environment) > 0 {
-		env := cmd.Env
-		for key, value := range m.config.Environment {
-			env = append(env, fmt.Sprintf("%s=%s", key, value))
+		delay := m.retryDelay(attempt)
+		m.logWarn("Transient failure (%s), retrying in %v (attempt %d of %d)",
+			GetErrorType(err), delay, attempt+1, retries)
+
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return output, err
+		}
+		if restart != nil {
+			restart()
 		}
-		cmd.Env = env
+	}
+}
+
+// executePipCommandOnce executes a single attempt of a pip command
+func (m *Manager) executePipCommandOnce(pipPath string, args []string, handler OutputHandler) (string, error) {
+	cmd := m.pipCommand(pipPath, args...)
+
+	var stdout, stderr *lineWriter
+	if handler != nil {
+		var mu sync.Mutex
+		stdout = newLineWriter(StreamStdout, handler, &mu)
+		stderr = newLineWriter(StreamStderr, handler, &mu)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
 	}
 
-	m.logDebug("Executing command: %s", cmd.String())
+	result, err := m.runCommand(cmd)
+	outputStr := result.Output
 
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
+	if handler != nil {
+		stdout.Flush()
+		stderr.Flush()
+	}
 
 	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			return outputStr, m.createPipError(cmd.String(), outputStr, exitError.ExitCode(), err)
-		}
-		return outputStr, m.createPipError(cmd.String(), outputStr, -1, err)
+		return outputStr, m.createPipError(cmd.String(), outputStr, result.ExitCode, err)
 	}
 
 	m.logDebug("Command output: %s", outputStr)
 	return outputStr, nil
 }
 
+// pipCommand builds a Command for pip, splitting commands like "python -m pip"
+func (m *Manager) pipCommand(pipPath string, args ...string) *Command {
+	// "<python> -m pip" keeps the interpreter path intact even if it contains spaces
+	if python := strings.TrimSuffix(pipPath, pipModuleSuffix); python != pipPath {
+		return &Command{Name: python, Args: append([]string{"-m", "pip"}, args...)}
+	}
+	if strings.Contains(pipPath, " ") {
+		parts := strings.Fields(pipPath)
+		return &Command{Name: parts[0], Args: append(parts[1:], args...)}
+	}
+	return &Command{Name: pipPath, Args: args}
+}
+
 // parseListOutput parses pip list output in text format
 func (m *Manager) parseListOutput(output string) []*Package {
 	var packages []*Package
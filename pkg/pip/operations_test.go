@@ -2,6 +2,7 @@ package pip
 
 import (
 	"context"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -326,24 +327,26 @@ func TestIntegrationSearchPackages(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
+	// Listing the whole PyPI index downloads tens of megabytes; TestSearchPackages
+	// covers the same code against a local index
+	if os.Getenv("RUN_NETWORK_TESTS") != "1" {
+		t.Skip("Skipping network search test (set RUN_NETWORK_TESTS=1 to enable)")
+	}
+
 	manager := setupTestManager(t)
 	skipIfNoPip(t, manager)
 
-	// Test search (should return error since pip search is disabled)
+	// Search queries the default index over the network
 	results, err := manager.SearchPackages("requests")
-
-	// We expect an error since pip search is disabled
-	if err == nil {
-		t.Error("SearchPackages() should return error since pip search is disabled")
+	if IsErrorType(err, ErrorTypeNetworkError) {
+		t.Skipf("package index not reachable: %v", err)
 	}
-
-	if results != nil {
-		t.Error("SearchPackages() should return nil results when disabled")
+	if err != nil {
+		t.Fatalf("SearchPackages() error = %v", err)
 	}
 
-	// Check if it's the expected error type
-	if !strings.Contains(err.Error(), "disabled") {
-		t.Errorf("Expected 'disabled' error, got: %v", err)
+	if len(results) == 0 || normalizePackageName(results[0].Name) != "requests" || results[0].Score != 1 {
+		t.Errorf("SearchPackages() first result = %+v, want an exact match for requests", results)
 	}
 }
 
@@ -604,31 +607,6 @@ func TestShowPackageValidation(t *testing.T) {
 	}
 }
 
-func TestSearchPackagesDisabled(t *testing.T) {
-	manager := NewManager(nil)
-
-	// Test that search returns appropriate error since it's disabled
-	results, err := manager.SearchPackages("requests")
-
-	if err == nil {
-		t.Error("SearchPackages() should return error since pip search is disabled")
-	}
-
-	if results != nil {
-		t.Error("SearchPackages() should return nil results when disabled")
-	}
-
-	if !strings.Contains(err.Error(), "disabled") {
-		t.Errorf("Expected 'disabled' error, got: %v", err)
-	}
-
-	// Test empty query
-	_, err = manager.SearchPackages("")
-	if err == nil {
-		t.Error("SearchPackages() with empty query should return error")
-	}
-}
-
 func TestExecutePipCommandWithOutputDetailed(t *testing.T) {
 	manager := NewManager(nil)
 
@@ -739,3 +717,53 @@ func TestExecutePipCommandWithOutputInvalidPipPath(t *testing.T) {
 		t.Error("executePipCommandWithOutput() should fail with invalid pip path")
 	}
 }
+
+func TestListPackagesWithOptions(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "list", "--format=json", "--not-required", "--local", "--exclude-editable",
+		"--exclude", "pip", "--exclude", "setuptools", "--verbose").Respond(
+		`[{"name": "requests", "version": "2.31.0", "location": "/venv/lib/python3.11/site-packages", "installer": "pip"}]`+"\n",
+		"", 0)
+	runner.On("python", "-m", "pip", "list", "--format=json", "--editable", "--path", "/opt/lib").Respond(
+		`[{"name": "mypkg", "version": "0.1.0", "editable_project_location": "/src/mypkg"}]`, "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	packages, err := manager.ListPackagesWithOptions(&ListOptions{
+		NotRequired:     true,
+		Local:           true,
+		ExcludeEditable: true,
+		Exclude:         []string{"pip", "setuptools"},
+		Verbose:         true,
+	})
+	if err != nil {
+		t.Fatalf("ListPackagesWithOptions() error: %v", err)
+	}
+	if len(packages) != 1 || packages[0].Location != "/venv/lib/python3.11/site-packages" || packages[0].Installer != "pip" {
+		t.Errorf("packages = %+v", packages)
+	}
+
+	packages, err = manager.ListPackagesWithOptions(&ListOptions{Editable: true, Path: []string{"/opt/lib"}})
+	if err != nil {
+		t.Fatalf("ListPackagesWithOptions() error: %v", err)
+	}
+	if len(packages) != 1 || !packages[0].Editable || packages[0].EditableLocation != "/src/mypkg" {
+		t.Errorf("editable packages = %+v", packages)
+	}
+}
+
+func TestListPackagesWithOptionsValidation(t *testing.T) {
+	manager := NewManager(nil)
+	manager.SetCommandRunner(NewFakeRunner())
+
+	for _, opts := range []*ListOptions{
+		{Editable: true, ExcludeEditable: true},
+		{User: true, Path: []string{"/opt/lib"}},
+		{Local: true, Path: []string{"/opt/lib"}},
+	} {
+		if _, err := manager.ListPackagesWithOptions(opts); !IsErrorType(err, ErrorTypeInvalidConfig) {
+			t.Errorf("ListPackagesWithOptions(%+v) error = %v, want %s", opts, err, ErrorTypeInvalidConfig)
+		}
+	}
+}
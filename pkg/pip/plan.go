@@ -0,0 +1,213 @@
+package pip
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// PlanAction is the kind of change a plan step makes
+type PlanAction string
+
+const (
+	PlanRunCommand PlanAction = "run_command"
+	PlanWriteFile  PlanAction = "write_file"
+	PlanCreateDir  PlanAction = "create_dir"
+	PlanRemoveDir  PlanAction = "remove_dir"
+	PlanDownload   PlanAction = "download"
+)
+
+// PlanStep is a single change that an operation would make
+type PlanStep struct {
+	Action      PlanAction `json:"action"`
+	Command     *Command   `json:"command,omitempty"`
+	Path        string     `json:"path,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// Plan lists the changes recorded while Config.DryRun is set.
+//
+// Operations assume that every planned step succeeds, so fallbacks that are
+// only used when an earlier step fails are not part of the plan.
+type Plan struct {
+	Steps []*PlanStep `json:"steps"`
+}
+
+// Commands returns the commands the plan would run
+func (p *Plan) Commands() []*Command {
+	var commands []*Command
+	for _, step := range p.Steps {
+		if step.Action == PlanRunCommand {
+			commands = append(commands, step.Command)
+		}
+	}
+	return commands
+}
+
+// FilesWritten returns the files the plan would create or overwrite
+func (p *Plan) FilesWritten() []string {
+	return p.paths(PlanWriteFile, PlanDownload)
+}
+
+// DirectoriesRemoved returns the directories the plan would delete
+func (p *Plan) DirectoriesRemoved() []string {
+	return p.paths(PlanRemoveDir)
+}
+
+// paths returns the paths of steps with one of the given actions
+func (p *Plan) paths(actions ...PlanAction) []string {
+	var paths []string
+	for _, step := range p.Steps {
+		for _, action := range actions {
+			if step.Action == action {
+				paths = append(paths, step.Path)
+			}
+		}
+	}
+	return paths
+}
+
+// String renders the plan for review, one step per line
+func (p *Plan) String() string {
+	var b strings.Builder
+	for i, step := range p.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	return b.String()
+}
+
+// String describes the step, e.g. "write_file /src/app/setup.py"
+func (s *PlanStep) String() string {
+	var target string
+	switch s.Action {
+	case PlanRunCommand:
+		target = s.Command.String()
+	case PlanDownload:
+		target = s.URL + " -> " + s.Path
+	default:
+		target = s.Path
+	}
+
+	if s.Description != "" {
+		return fmt.Sprintf("%s %s  # %s", s.Action, target, s.Description)
+	}
+	return fmt.Sprintf("%s %s", s.Action, target)
+}
+
+// Plan returns the steps recorded by dry-run operations since the manager
+// was created or ResetPlan was called
+func (m *Manager) Plan() *Plan {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plan := &Plan{}
+	if m.plan != nil {
+		plan.Steps = append(plan.Steps, m.plan.Steps...)
+	}
+	return plan
+}
+
+// ResetPlan discards the recorded dry-run steps
+func (m *Manager) ResetPlan() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plan = nil
+}
+
+// DryRun calls fn with a dry-run copy of the manager and returns the steps
+// it planned. The copy is a scoped manager, see ForVenv.
+func (m *Manager) DryRun(fn func(m *Manager) error) (*Plan, error) {
+	config := copyConfig(m.currentConfig())
+	config.DryRun = true
+
+	scoped := m.clone(config)
+	err := fn(scoped)
+	return scoped.Plan(), err
+}
+
+// dryRun reports whether operations should be planned instead of executed
+func (m *Manager) dryRun() bool {
+	return m.currentConfig().DryRun
+}
+
+// planStep records a step in the dry-run plan
+func (m *Manager) planStep(step *PlanStep) {
+	m.logInfo("Dry run: %s", step)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.plan == nil {
+		m.plan = &Plan{}
+	}
+	m.plan.Steps = append(m.plan.Steps, step)
+}
+
+// runPlanned runs a command that changes the system. In dry-run mode the
+// command is added to the plan and reported as successful instead.
+func (m *Manager) runPlanned(cmd *Command, description string) (*CommandResult, error) {
+	if m.dryRun() {
+		m.planStep(&PlanStep{Action: PlanRunCommand, Command: cmd, Description: description})
+		return &CommandResult{}, nil
+	}
+	return m.runCommand(cmd)
+}
+
+// planPipCommand adds a pip command to the plan when in dry-run mode and
+// reports whether it did so, in which case the caller must not run it
+func (m *Manager) planPipCommand(pipPath string, args []string, description string) bool {
+	if !m.dryRun() {
+		return false
+	}
+	cmd := m.pipCommand(pipPath, m.applyGlobalOptions(args)...)
+	m.planStep(&PlanStep{Action: PlanRunCommand, Command: cmd, Description: description})
+	return true
+}
+
+// writeFile writes a file, or plans the write in dry-run mode
+func (m *Manager) writeFile(path string, data []byte, perm os.FileMode) error {
+	if m.dryRun() {
+		m.planStep(&PlanStep{Action: PlanWriteFile, Path: path})
+		return nil
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// createFile creates a file for writing, or plans the write in dry-run mode
+// and returns a writer that discards its input
+func (m *Manager) createFile(path string) (io.WriteCloser, error) {
+	if m.dryRun() {
+		m.planStep(&PlanStep{Action: PlanWriteFile, Path: path})
+		return nopWriteCloser{io.Discard}, nil
+	}
+	return os.Create(path)
+}
+
+// mkdirAll creates a directory tree, or plans it in dry-run mode.
+// Directories that already exist are not added to the plan.
+func (m *Manager) mkdirAll(path string, perm os.FileMode) error {
+	if m.dryRun() {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			m.planStep(&PlanStep{Action: PlanCreateDir, Path: path})
+		}
+		return nil
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// removeAll removes a directory tree, or plans it in dry-run mode
+func (m *Manager) removeAll(path string) error {
+	if m.dryRun() {
+		m.planStep(&PlanStep{Action: PlanRemoveDir, Path: path})
+		return nil
+	}
+	return os.RemoveAll(path)
+}
+
+// nopWriteCloser adds a no-op Close to a writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
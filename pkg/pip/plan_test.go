@@ -0,0 +1,150 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newDryRunManager(t *testing.T) (*Manager, *FakeRunner) {
+	t.Helper()
+
+	runner := NewFakeRunner()
+	manager := NewManager(&Config{DryRun: true, DefaultIndex: "https://pypi.internal/simple"})
+	manager.SetCommandRunner(runner)
+	return manager, runner
+}
+
+func TestDryRunPackageOperations(t *testing.T) {
+	manager, runner := newDryRunManager(t)
+
+	if err := manager.InstallPackage(&PackageSpec{Name: "requests", Version: ">=2.0"}); err != nil {
+		t.Fatalf("InstallPackage() error: %v", err)
+	}
+	if err := manager.UninstallPackage("six"); err != nil {
+		t.Fatalf("UninstallPackage() error: %v", err)
+	}
+
+	requirements := filepath.Join(t.TempDir(), "requirements.txt")
+	if err := os.WriteFile(requirements, []byte("flask\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.InstallRequirements(requirements); err != nil {
+		t.Fatalf("InstallRequirements() error: %v", err)
+	}
+
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Errorf("dry run executed %d commands", len(calls))
+	}
+
+	commands := manager.Plan().Commands()
+	want := []string{
+		"python -m pip install requests>=2.0 --index-url https://pypi.internal/simple",
+		"python -m pip uninstall -y six",
+		"python -m pip install -r " + requirements + " --index-url https://pypi.internal/simple",
+	}
+	if len(commands) != len(want) {
+		t.Fatalf("planned %d commands, want %d:\n%s", len(commands), len(want), manager.Plan())
+	}
+	for i, cmd := range commands {
+		if cmd.String() != want[i] {
+			t.Errorf("command %d = %q, want %q", i, cmd.String(), want[i])
+		}
+	}
+
+	manager.ResetPlan()
+	if steps := manager.Plan().Steps; len(steps) != 0 {
+		t.Errorf("ResetPlan() left %d steps", len(steps))
+	}
+}
+
+func TestDryRunFilesystemOperations(t *testing.T) {
+	manager, runner := newDryRunManager(t)
+	dir := t.TempDir()
+
+	venvPath := filepath.Join(dir, "venv")
+	if err := os.MkdirAll(venvPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RemoveVenv(venvPath); err != nil {
+		t.Fatalf("RemoveVenv() error: %v", err)
+	}
+	if _, err := os.Stat(venvPath); err != nil {
+		t.Errorf("dry run removed the venv: %v", err)
+	}
+
+	projectPath := filepath.Join(dir, "project")
+	opts := &ProjectOptions{Name: "demo", CreateVenv: true, ExtraFiles: map[string]string{"docs/index.md": "# Demo"}}
+	if err := manager.InitProject(projectPath, opts); err != nil {
+		t.Fatalf("InitProject() error: %v", err)
+	}
+	if _, err := os.Stat(projectPath); !os.IsNotExist(err) {
+		t.Errorf("dry run created the project directory")
+	}
+
+	plan := manager.Plan()
+	if removed := plan.DirectoriesRemoved(); len(removed) != 1 || removed[0] != venvPath {
+		t.Errorf("DirectoriesRemoved() = %v", removed)
+	}
+
+	files := strings.Join(plan.FilesWritten(), "\n")
+	for _, name := range []string{"setup.py", "pyproject.toml", "README.md", "__init__.py", "index.md"} {
+		if !strings.Contains(files, name) {
+			t.Errorf("FilesWritten() missing %s:\n%s", name, files)
+		}
+	}
+
+	commands := plan.Commands()
+	if len(commands) != 1 || !strings.Contains(commands[0].String(), "-m venv "+filepath.Join(projectPath, "venv")) {
+		t.Errorf("Commands() = %v", commands)
+	}
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Errorf("dry run executed %d commands", len(calls))
+	}
+}
+
+func TestDryRunInstall(t *testing.T) {
+	manager, runner := newDryRunManager(t)
+
+	if err := manager.Install(); err != nil {
+		t.Fatalf("Install() error: %v", err)
+	}
+
+	if len(manager.Plan().Commands()) == 0 {
+		t.Error("Install() planned no commands")
+	}
+
+	// Only read-only availability checks may run
+	for _, call := range runner.Calls() {
+		if !strings.HasSuffix(call.String(), "--version") {
+			t.Errorf("dry run executed %s", call)
+		}
+	}
+}
+
+func TestManagerDryRun(t *testing.T) {
+	runner := NewFakeRunner()
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	plan, err := manager.DryRun(func(m *Manager) error {
+		return m.InstallPackage(&PackageSpec{Name: "requests"})
+	})
+	if err != nil {
+		t.Fatalf("DryRun() error: %v", err)
+	}
+
+	if len(plan.Steps) != 1 || plan.Steps[0].Action != PlanRunCommand {
+		t.Errorf("DryRun() plan = %s", plan)
+	}
+	if !strings.Contains(plan.String(), "1. run_command python -m pip install requests") {
+		t.Errorf("Plan.String() = %q", plan.String())
+	}
+	if manager.GetConfig().DryRun || len(manager.Plan().Steps) != 0 {
+		t.Error("DryRun() should not affect the parent manager")
+	}
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Errorf("dry run executed %d commands", len(calls))
+	}
+}
@@ -0,0 +1,162 @@
+package pip
+
+import (
+	"sort"
+	"strings"
+)
+
+// PackageChange is the effect of an install on one package
+type PackageChange struct {
+	Name      string `json:"name"`
+	From      string `json:"from,omitempty"` // installed version, empty for new packages
+	To        string `json:"to"`
+	Requested bool   `json:"requested,omitempty"` // named in the install rather than a dependency
+}
+
+// InstallPreview is what an install would change in the environment
+type InstallPreview struct {
+	Install   []*PackageChange `json:"install,omitempty"`
+	Upgrade   []*PackageChange `json:"upgrade,omitempty"`
+	Downgrade []*PackageChange `json:"downgrade,omitempty"`
+	Unchanged []*PackageChange `json:"unchanged,omitempty"`
+
+	// Report is pip's resolution of the whole requirement set
+	Report *InstallReport `json:"report"`
+}
+
+// HasChanges reports whether the install would add or change any package
+func (p *InstallPreview) HasChanges() bool {
+	return len(p.Install)+len(p.Upgrade)+len(p.Downgrade) > 0
+}
+
+// PreviewInstall resolves packages as InstallPackages would, taking the
+// installed packages into account, without installing anything. It runs
+// "pip install --dry-run --report", which reports only the packages pip
+// would install, and compares them with the installed versions. Requested
+// packages that are already satisfied are listed as unchanged; satisfied
+// dependencies are not listed. It requires pip 22.2 or newer and runs even
+// in dry-run mode, as it does not change the environment.
+func (m *Manager) PreviewInstall(pkgs []*PackageSpec) (*InstallPreview, error) {
+	if len(pkgs) == 0 {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPackageSpec),
+			Message: "no packages to preview",
+		}
+	}
+	for _, pkg := range pkgs {
+		if err := m.validatePackageSpec(pkg); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.checkWheelhouse(pkgs, nil); err != nil {
+		return nil, err
+	}
+
+	args, err := buildBatchInstallArgs(pkgs, &InstallOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	m.logInfo("Previewing install of %d packages", len(pkgs))
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return nil, ErrPipNotInstalled
+	}
+
+	reportPath, cleanup, err := reportFile()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	args = append(args, "--dry-run", "--quiet", "--report", reportPath)
+	if output, err := m.executePipCommandWithOutput(pipPath, args); err != nil {
+		return nil, reportOptionError(err, strings.Split(output, "\n"))
+	}
+
+	report, err := readInstallReport(reportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := m.ListPackages()
+	if err != nil {
+		return nil, err
+	}
+	return diffInstallReport(report, installed, pkgs), nil
+}
+
+// diffInstallReport sorts the items of a report into new, upgraded,
+// downgraded and unchanged packages, by name. Requested packages missing
+// from the report are already satisfied and count as unchanged.
+func diffInstallReport(report *InstallReport, installed []*Package, requested []*PackageSpec) *InstallPreview {
+	versions := make(map[string]string, len(installed))
+	for _, pkg := range installed {
+		versions[normalizePackageName(pkg.Name)] = pkg.Version
+	}
+
+	preview := &InstallPreview{Report: report}
+	for _, item := range report.Install {
+		change := &PackageChange{
+			Name:      item.Name(),
+			From:      versions[normalizePackageName(item.Name())],
+			To:        item.Version(),
+			Requested: item.Requested,
+		}
+
+		switch cmp := compareVersionStrings(change.From, change.To); {
+		case change.From == "":
+			preview.Install = append(preview.Install, change)
+		case cmp < 0:
+			preview.Upgrade = append(preview.Upgrade, change)
+		case cmp > 0:
+			preview.Downgrade = append(preview.Downgrade, change)
+		default:
+			preview.Unchanged = append(preview.Unchanged, change)
+		}
+	}
+
+	for _, pkg := range requested {
+		key := normalizePackageName(pkg.Name)
+		if version, ok := versions[key]; ok && report.Find(pkg.Name) == nil && !containsChange(preview.Unchanged, key) {
+			preview.Unchanged = append(preview.Unchanged, &PackageChange{
+				Name:      pkg.Name,
+				From:      version,
+				To:        version,
+				Requested: true,
+			})
+		}
+	}
+
+	for _, changes := range [][]*PackageChange{preview.Install, preview.Upgrade, preview.Downgrade, preview.Unchanged} {
+		sort.Slice(changes, func(i, j int) bool {
+			return normalizePackageName(changes[i].Name) < normalizePackageName(changes[j].Name)
+		})
+	}
+	return preview
+}
+
+// containsChange reports whether changes include the normalized name key
+func containsChange(changes []*PackageChange, key string) bool {
+	for _, change := range changes {
+		if normalizePackageName(change.Name) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// compareVersionStrings compares two versions under PEP 440, falling back
+// to treating different unparseable versions as an upgrade
+func compareVersionStrings(a, b string) int {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	if errA == nil && errB == nil {
+		return va.Compare(vb)
+	}
+	if a == b {
+		return 0
+	}
+	return -1
+}
@@ -0,0 +1,93 @@
+package pip
+
+import (
+	"testing"
+)
+
+const testPreviewReport = `{
+  "version": "1",
+  "pip_version": "24.0",
+  "install": [
+    {"download_info": {"url": "https://example.com/requests-2.31.0-py3-none-any.whl", "archive_info": {}}, "requested": true, "metadata": {"name": "requests", "version": "2.31.0"}},
+    {"download_info": {"url": "https://example.com/urllib3-1.26.18-py2.py3-none-any.whl", "archive_info": {}}, "requested": false, "metadata": {"name": "urllib3", "version": "1.26.18"}},
+    {"download_info": {"url": "https://example.com/charset_normalizer-3.3.2-py3-none-any.whl", "archive_info": {}}, "requested": false, "metadata": {"name": "charset-normalizer", "version": "3.3.2"}},
+    {"download_info": {"url": "https://example.com/certifi-2024.2.2-py3-none-any.whl", "archive_info": {}}, "requested": false, "metadata": {"name": "certifi", "version": "2024.2.2"}}
+  ]
+}`
+
+func TestPreviewInstall(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests==2.31.0", "Six", "--dry-run", "--quiet", "--report", "*").
+		Do(writeReport(t, testPreviewReport)).
+		Respond("", "", 0)
+	runner.On("python", "-m", "pip", "list", "...").Respond(
+		`[{"name": "requests", "version": "2.28.0"}, {"name": "urllib3", "version": "2.0.7"}, `+
+			`{"name": "idna", "version": "3.6"}, {"name": "certifi", "version": "2023.7.22"}, {"name": "six", "version": "1.16.0"}]`, "", 0)
+
+	// Previews do not change the environment, so they run in dry-run mode too
+	manager := NewManager(&Config{DryRun: true})
+	manager.SetCommandRunner(runner)
+
+	preview, err := manager.PreviewInstall([]*PackageSpec{{Name: "requests", Version: "==2.31.0"}, {Name: "Six"}})
+	if err != nil {
+		t.Fatalf("PreviewInstall() error: %v", err)
+	}
+
+	check := func(kind string, changes []*PackageChange, want ...string) {
+		t.Helper()
+		if len(changes) != len(want)/2 {
+			t.Errorf("%s = %d changes, want %d", kind, len(changes), len(want)/2)
+			return
+		}
+		for i, change := range changes {
+			if got := change.Name + " " + change.From + "->" + change.To; got != want[2*i]+" "+want[2*i+1] {
+				t.Errorf("%s[%d] = %s, want %s %s", kind, i, got, want[2*i], want[2*i+1])
+			}
+		}
+	}
+	check("install", preview.Install, "charset-normalizer", "->3.3.2")
+	check("upgrade", preview.Upgrade, "certifi", "2023.7.22->2024.2.2", "requests", "2.28.0->2.31.0")
+	check("downgrade", preview.Downgrade, "urllib3", "2.0.7->1.26.18")
+	// pip leaves out satisfied packages; only requested ones are listed
+	check("unchanged", preview.Unchanged, "Six", "1.16.0->1.16.0")
+
+	if !preview.HasChanges() || preview.Report == nil || !preview.Upgrade[1].Requested {
+		t.Errorf("preview = %+v", preview)
+	}
+	if len(manager.Plan().Commands()) != 0 {
+		t.Error("preview should not be planned")
+	}
+}
+
+func TestPreviewInstallNoChanges(t *testing.T) {
+	installed := []*Package{{Name: "six", Version: "1.16"}, {Name: "idna", Version: "3.6"}}
+
+	// A reinstall at the same version
+	preview := diffInstallReport(&InstallReport{Install: []*InstallReportItem{
+		{Metadata: &ReportMetadata{Name: "six", Version: "1.16.0"}, Requested: true},
+	}}, installed, []*PackageSpec{{Name: "six"}})
+	if preview.HasChanges() || len(preview.Unchanged) != 1 {
+		t.Errorf("preview = %+v, want six unchanged", preview)
+	}
+
+	// Already satisfied, so pip reports nothing
+	preview = diffInstallReport(&InstallReport{}, installed, []*PackageSpec{{Name: "six"}, {Name: "six", Version: ">=1"}})
+	if preview.HasChanges() || len(preview.Unchanged) != 1 || preview.Unchanged[0].From != "1.16" {
+		t.Errorf("preview = %+v, want six unchanged once", preview)
+	}
+}
+
+func TestPreviewInstallOldPip(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "...").Respond("", "no such option: --dry-run\n", 2)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.PreviewInstall([]*PackageSpec{{Name: "six"}}); !IsErrorType(err, ErrorTypeFeatureDisabled) {
+		t.Errorf("error = %v, want feature_disabled", err)
+	}
+	if _, err := manager.PreviewInstall(nil); !IsErrorType(err, ErrorTypeInvalidPackageSpec) {
+		t.Errorf("empty preview: error = %v, want invalid_package_spec", err)
+	}
+}
@@ -0,0 +1,83 @@
+//go:build !windows
+
+package pip
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts the command as the leader of a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// groupPollInterval is how often terminateProcessGroup checks whether the
+// rest of the group has exited after the leader
+const groupPollInterval = 20 * time.Millisecond
+
+// terminateProcessGroup sends SIGTERM to the process group and SIGKILL if
+// any member is still running after grace. done is closed once the leader
+// has been reaped; its children keep the rest of the grace period.
+func terminateProcessGroup(process *os.Process, grace time.Duration, done <-chan struct{}) {
+	pgid := -process.Pid
+	if err := syscall.Kill(pgid, syscall.SIGTERM); err != nil {
+		process.Kill()
+		return
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		syscall.Kill(pgid, syscall.SIGKILL)
+		return
+	}
+
+	// The leader exited; wait for the processes it spawned, such as
+	// setup.py builds and compilers, to finish cleaning up
+	ticker := time.NewTicker(groupPollInterval)
+	defer ticker.Stop()
+
+	for syscall.Kill(pgid, 0) != syscall.ESRCH {
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			syscall.Kill(pgid, syscall.SIGKILL)
+			return
+		}
+	}
+}
+
+// limitCommand wraps the command in a shell that applies the resource limits
+// with ulimit before exec'ing it, so they are inherited by every child
+func limitCommand(name string, args []string, limits ResourceLimits) (string, []string) {
+	var ulimits []string
+	if limits.MaxMemoryBytes > 0 {
+		kib := limits.MaxMemoryBytes / 1024
+		if kib == 0 {
+			kib = 1
+		}
+		ulimits = append(ulimits, "ulimit -v "+strconv.FormatUint(kib, 10))
+	}
+	if limits.MaxCPUSeconds > 0 {
+		ulimits = append(ulimits, "ulimit -t "+strconv.FormatUint(limits.MaxCPUSeconds, 10))
+	}
+	if limits.MaxOpenFiles > 0 {
+		ulimits = append(ulimits, "ulimit -n "+strconv.FormatUint(limits.MaxOpenFiles, 10))
+	}
+
+	if len(ulimits) == 0 {
+		return name, args
+	}
+
+	// The command is passed as positional parameters so it is never parsed by the shell
+	script := strings.Join(ulimits, " && ") + ` && exec "$0" "$@"`
+	return "/bin/sh", append([]string{"-c", script, name}, args...)
+}
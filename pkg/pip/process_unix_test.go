@@ -0,0 +1,155 @@
+//go:build !windows
+
+package pip
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLimitCommand(t *testing.T) {
+	name, args := limitCommand("pip", []string{"install", "x"}, ResourceLimits{})
+	if name != "pip" || !reflect.DeepEqual(args, []string{"install", "x"}) {
+		t.Errorf("limitCommand() without limits = %s %v", name, args)
+	}
+
+	name, args = limitCommand("pip", []string{"install", "x; rm -rf /"}, ResourceLimits{
+		MaxMemoryBytes: 2 << 30,
+		MaxCPUSeconds:  600,
+		MaxOpenFiles:   256,
+	})
+	want := []string{
+		"-c",
+		`ulimit -v 2097152 && ulimit -t 600 && ulimit -n 256 && exec "$0" "$@"`,
+		"pip", "install", "x; rm -rf /",
+	}
+	if name != "/bin/sh" || !reflect.DeepEqual(args, want) {
+		t.Errorf("limitCommand() = %s %q, want /bin/sh %q", name, args, want)
+	}
+}
+
+func TestExecRunnerResourceLimits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping subprocess test in short mode")
+	}
+
+	runner := NewExecRunner()
+	result, err := runner.Run(context.Background(), &Command{
+		Name:   "sh",
+		Args:   []string{"-c", "ulimit -n"},
+		Limits: ResourceLimits{MaxOpenFiles: 64},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if got := strings.TrimSpace(result.Stdout); got != "64" {
+		t.Errorf("open file limit = %q, want 64", got)
+	}
+}
+
+func TestExecRunnerKillsProcessGroup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping subprocess test in short mode")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan string, 1)
+
+	stdout := &syncBuffer{}
+	go func() {
+		// Wait for the background child's pid, then cancel
+		for i := 0; i < 100; i++ {
+			time.Sleep(20 * time.Millisecond)
+			if output := stdout.String(); strings.HasSuffix(output, "\n") {
+				started <- strings.TrimSpace(output)
+				break
+			}
+		}
+		cancel()
+	}()
+
+	runner := NewExecRunner()
+	start := time.Now()
+	_, err := runner.Run(ctx, &Command{
+		Name:            "sh",
+		Args:            []string{"-c", `trap "" TERM; sleep 30 & echo $!; wait`},
+		Stdout:          stdout,
+		KillGracePeriod: 200 * time.Millisecond,
+	})
+	if err == nil {
+		t.Fatal("Run() should fail when the context is cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run() took %v after cancellation", elapsed)
+	}
+
+	var pid int
+	select {
+	case line := <-started:
+		pid, _ = strconv.Atoi(line)
+	default:
+	}
+	if pid == 0 {
+		t.Fatal("background child pid was not reported")
+	}
+
+	// The orphaned sleep must be gone; allow a moment for it to be reaped
+	deadline := time.Now().Add(2 * time.Second)
+	for syscall.Kill(pid, 0) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("child process %d survived cancellation", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestExecRunnerGraceAfterLeaderExits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping subprocess test in short mode")
+	}
+
+	marker := filepath.Join(t.TempDir(), "cleaned")
+	ctx, cancel := context.WithCancel(context.Background())
+	stdout := &syncBuffer{}
+	go func() {
+		for i := 0; i < 100 && !strings.HasSuffix(stdout.String(), "\n"); i++ {
+			time.Sleep(20 * time.Millisecond)
+		}
+		cancel()
+	}()
+
+	// The leader exits on SIGTERM at once; its child needs a moment to
+	// clean up and does not hold the output pipes
+	script := `(trap 'sleep 0.3; echo done > "$0"; exit 0' TERM; while :; do sleep 0.05; done) >/dev/null 2>&1 &
+echo started; wait`
+
+	runner := NewExecRunner()
+	_, err := runner.Run(ctx, &Command{
+		Name:            "sh",
+		Args:            []string{"-c", script, marker},
+		Stdout:          stdout,
+		KillGracePeriod: 5 * time.Second,
+	})
+	if err == nil {
+		t.Fatal("Run() should fail when the context is cancelled")
+	}
+
+	// terminateProcessGroup runs in the background; give it time to finish
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if _, err := os.Stat(marker); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("child was killed before finishing its SIGTERM handler")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package pip
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts the command in a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminateProcessGroup kills the process tree with taskkill. Console
+// processes in another group cannot be asked to exit gracefully, so the
+// grace period is not used on Windows.
+func terminateProcessGroup(process *os.Process, grace time.Duration, done <-chan struct{}) {
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(process.Pid))
+	if err := kill.Run(); err != nil {
+		process.Kill()
+	}
+}
+
+// limitCommand returns the command unchanged; resource limits are not
+// supported on Windows
+func limitCommand(name string, args []string, limits ResourceLimits) (string, []string) {
+	return name, args
+}
@@ -0,0 +1,237 @@
+package pip
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProgressEventType represents a phase of a pip install run
+type ProgressEventType string
+
+const (
+	ProgressCollecting    ProgressEventType = "collecting"
+	ProgressDownloading   ProgressEventType = "downloading"
+	ProgressUsingCached   ProgressEventType = "using_cached"
+	ProgressBuildingWheel ProgressEventType = "building_wheel"
+	ProgressBuilt         ProgressEventType = "built"
+	ProgressInstalling    ProgressEventType = "installing"
+	ProgressInstalled     ProgressEventType = "installed"
+)
+
+// ProgressEvent is a typed event parsed from a line of pip install output
+type ProgressEvent struct {
+	Type        ProgressEventType `json:"type"`
+	Package     string            `json:"package,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Requirement string            `json:"requirement,omitempty"` // as given to "Collecting"
+	Filename    string            `json:"filename,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Size        string            `json:"size,omitempty"` // as printed by pip, e.g. "62 kB"
+	SizeBytes   int64             `json:"size_bytes,omitempty"`
+	Packages    []*Package        `json:"packages,omitempty"` // for built, installing and installed events
+	Line        string            `json:"line"`
+}
+
+// ProgressReporter receives progress events from install operations
+type ProgressReporter interface {
+	Report(event *ProgressEvent)
+}
+
+// ProgressReporterFunc adapts a function to the ProgressReporter interface
+type ProgressReporterFunc func(event *ProgressEvent)
+
+// Report calls f(event)
+func (f ProgressReporterFunc) Report(event *ProgressEvent) {
+	f(event)
+}
+
+// SetProgressReporter sets the reporter that receives install progress events
+func (m *Manager) SetProgressReporter(reporter ProgressReporter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progress = reporter
+}
+
+var (
+	requirementNameRegex  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+	fileSizeRegex         = regexp.MustCompile(`^([0-9.]+)\s*([A-Za-z]*)$`)
+	packageNameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// ParseProgressLine parses a single line of pip install output.
+// It returns nil for lines that do not describe a progress phase.
+func ParseProgressLine(line string) *ProgressEvent {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(trimmed, "Collecting "):
+		req := strings.TrimPrefix(trimmed, "Collecting ")
+		if idx := strings.Index(req, " (from "); idx >= 0 {
+			req = req[:idx]
+		}
+		event := &ProgressEvent{Type: ProgressCollecting, Requirement: req, Line: line}
+		if !strings.Contains(req, "://") {
+			event.Package = requirementNameRegex.FindString(req)
+		}
+		return event
+
+	case strings.HasPrefix(trimmed, "Downloading "):
+		return parseFileEvent(ProgressDownloading, strings.TrimPrefix(trimmed, "Downloading "), line)
+
+	case strings.HasPrefix(trimmed, "Using cached "):
+		return parseFileEvent(ProgressUsingCached, strings.TrimPrefix(trimmed, "Using cached "), line)
+
+	case strings.HasPrefix(trimmed, "Building wheel for "):
+		fields := strings.Fields(strings.TrimPrefix(trimmed, "Building wheel for "))
+		if len(fields) == 0 {
+			return nil
+		}
+		return &ProgressEvent{Type: ProgressBuildingWheel, Package: fields[0], Line: line}
+
+	case strings.HasPrefix(trimmed, "Successfully built "):
+		event := &ProgressEvent{Type: ProgressBuilt, Line: line}
+		for _, name := range strings.Fields(strings.TrimPrefix(trimmed, "Successfully built ")) {
+			event.Packages = append(event.Packages, &Package{Name: name})
+		}
+		return event
+
+	case strings.HasPrefix(trimmed, "Installing collected packages:"):
+		event := &ProgressEvent{Type: ProgressInstalling, Line: line}
+		for _, name := range strings.Split(strings.TrimPrefix(trimmed, "Installing collected packages:"), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				event.Packages = append(event.Packages, &Package{Name: name})
+			}
+		}
+		return event
+
+	case strings.HasPrefix(trimmed, "Successfully installed "):
+		event := &ProgressEvent{Type: ProgressInstalled, Line: line}
+		for _, item := range strings.Fields(strings.TrimPrefix(trimmed, "Successfully installed ")) {
+			name, version := splitNameVersion(item)
+			event.Packages = append(event.Packages, &Package{Name: name, Version: version})
+		}
+		return event
+	}
+
+	return nil
+}
+
+// ParseProgressOutput parses every progress event from pip install output
+func ParseProgressOutput(output string) []*ProgressEvent {
+	var events []*ProgressEvent
+	for _, line := range strings.Split(output, "\n") {
+		if event := ParseProgressLine(strings.TrimRight(line, "\r")); event != nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// parseFileEvent parses "<file-or-url> (<size>)" from Downloading and Using cached lines
+func parseFileEvent(eventType ProgressEventType, rest, line string) *ProgressEvent {
+	event := &ProgressEvent{Type: eventType, Line: line}
+
+	if idx := strings.LastIndex(rest, " ("); idx >= 0 && strings.HasSuffix(rest, ")") {
+		event.Size = strings.TrimSpace(rest[idx+2 : len(rest)-1])
+		event.SizeBytes = parseFileSize(event.Size)
+		rest = rest[:idx]
+	}
+
+	rest = strings.TrimSpace(rest)
+	if strings.Contains(rest, "://") {
+		event.URL = rest
+		event.Filename = path.Base(strings.SplitN(strings.SplitN(rest, "#", 2)[0], "?", 2)[0])
+	} else {
+		event.Filename = rest
+	}
+
+	event.Package, event.Version = splitDistFilename(event.Filename)
+	return event
+}
+
+// parseFileSize converts sizes like "62 kB", "1.2MB" or "512 bytes" to bytes
+func parseFileSize(size string) int64 {
+	matches := fileSizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0
+	}
+
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	multiplier := 1.0
+	switch strings.ToLower(matches[2]) {
+	case "", "b", "bytes":
+	case "kb":
+		multiplier = 1e3
+	case "mb":
+		multiplier = 1e6
+	case "gb":
+		multiplier = 1e9
+	case "kib":
+		multiplier = 1 << 10
+	case "mib":
+		multiplier = 1 << 20
+	case "gib":
+		multiplier = 1 << 30
+	default:
+		return 0
+	}
+
+	return int64(value * multiplier)
+}
+
+// splitNameVersion splits "name-version" as printed by "Successfully installed"
+func splitNameVersion(item string) (string, string) {
+	idx := strings.LastIndex(item, "-")
+	if idx <= 0 {
+		return item, ""
+	}
+	return item[:idx], item[idx+1:]
+}
+
+// normalizePackageName normalizes a project name as described in PEP 503
+func normalizePackageName(name string) string {
+	return strings.ToLower(packageNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// splitDistFilename extracts the project name and version from a wheel or sdist filename
+func splitDistFilename(filename string) (string, string) {
+	base := strings.TrimSuffix(filename, ".metadata")
+
+	if strings.HasSuffix(base, ".whl") {
+		parts := strings.Split(strings.TrimSuffix(base, ".whl"), "-")
+		if len(parts) >= 2 {
+			return parts[0], parts[1]
+		}
+		return "", ""
+	}
+
+	for _, ext := range []string{".tar.gz", ".tar.bz2", ".tgz", ".zip", ".tar"} {
+		if strings.HasSuffix(base, ext) {
+			return splitNameVersion(strings.TrimSuffix(base, ext))
+		}
+	}
+
+	return "", ""
+}
+
+// progressHandler returns an OutputHandler that reports parsed events
+func (m *Manager) progressHandler() OutputHandler {
+	m.mu.RLock()
+	reporter := m.progress
+	m.mu.RUnlock()
+
+	if reporter == nil {
+		return nil
+	}
+	return func(line OutputLine) {
+		if event := ParseProgressLine(line.Text); event != nil {
+			reporter.Report(event)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package pip
+
+import (
+	"testing"
+)
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		check func(t *testing.T, event *ProgressEvent)
+	}{
+		{
+			name: "collecting with origin",
+			line: "Collecting urllib3<3,>=1.21.1 (from requests)",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressCollecting || e.Package != "urllib3" || e.Requirement != "urllib3<3,>=1.21.1" {
+					t.Errorf("unexpected event: %+v", e)
+				}
+			},
+		},
+		{
+			name: "downloading wheel",
+			line: "  Downloading requests-2.31.0-py3-none-any.whl (62 kB)",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressDownloading || e.Package != "requests" || e.Version != "2.31.0" {
+					t.Errorf("unexpected event: %+v", e)
+				}
+				if e.Size != "62 kB" || e.SizeBytes != 62000 {
+					t.Errorf("Size = %q (%d)", e.Size, e.SizeBytes)
+				}
+			},
+		},
+		{
+			name: "downloading url",
+			line: "  Downloading https://files.example.com/packages/ab/cd/six-1.16.0.tar.gz#sha256=abc (34kB)",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.URL == "" || e.Filename != "six-1.16.0.tar.gz" || e.Package != "six" || e.Version != "1.16.0" {
+					t.Errorf("unexpected event: %+v", e)
+				}
+				if e.SizeBytes != 34000 {
+					t.Errorf("SizeBytes = %d", e.SizeBytes)
+				}
+			},
+		},
+		{
+			name: "using cached",
+			line: "  Using cached charset_normalizer-3.3.2-cp311-cp311-manylinux_2_17_x86_64.whl (140 kB)",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressUsingCached || e.Package != "charset_normalizer" || e.Version != "3.3.2" {
+					t.Errorf("unexpected event: %+v", e)
+				}
+			},
+		},
+		{
+			name: "building wheel",
+			line: "  Building wheel for pyyaml (pyproject.toml) ... done",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressBuildingWheel || e.Package != "pyyaml" {
+					t.Errorf("unexpected event: %+v", e)
+				}
+			},
+		},
+		{
+			name: "successfully built",
+			line: "Successfully built pyyaml simplejson",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressBuilt || len(e.Packages) != 2 {
+					t.Errorf("unexpected event: %+v", e)
+				}
+			},
+		},
+		{
+			name: "installing collected",
+			line: "Installing collected packages: urllib3, idna, requests",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressInstalling || len(e.Packages) != 3 || e.Packages[2].Name != "requests" {
+					t.Errorf("unexpected event: %+v", e)
+				}
+			},
+		},
+		{
+			name: "successfully installed",
+			line: "Successfully installed charset-normalizer-3.3.2 requests-2.31.0",
+			check: func(t *testing.T, e *ProgressEvent) {
+				if e.Type != ProgressInstalled || len(e.Packages) != 2 {
+					t.Fatalf("unexpected event: %+v", e)
+				}
+				if e.Packages[0].Name != "charset-normalizer" || e.Packages[0].Version != "3.3.2" {
+					t.Errorf("Packages[0] = %+v", e.Packages[0])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := ParseProgressLine(tt.line)
+			if event == nil {
+				t.Fatalf("ParseProgressLine(%q) returned nil", tt.line)
+			}
+			if event.Line != tt.line {
+				t.Errorf("Line = %q, want %q", event.Line, tt.line)
+			}
+			tt.check(t, event)
+		})
+	}
+
+	if event := ParseProgressLine("Requirement already satisfied: pip in /site-packages"); event != nil {
+		t.Errorf("unexpected event for unrelated line: %+v", event)
+	}
+}
+
+func TestParseFileSize(t *testing.T) {
+	tests := map[string]int64{
+		"62 kB":     62000,
+		"1.5MB":     1500000,
+		"512 bytes": 512,
+		"2 GiB":     2 << 30,
+		"unknown":   0,
+	}
+
+	for size, want := range tests {
+		if got := parseFileSize(size); got != want {
+			t.Errorf("parseFileSize(%q) = %d, want %d", size, got, want)
+		}
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests").Respond(`Collecting requests
+  Using cached requests-2.31.0-py3-none-any.whl (62 kB)
+Requirement already satisfied: idna in ./site-packages
+Installing collected packages: requests
+Successfully installed requests-2.31.0
+`, "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	var events []*ProgressEvent
+	manager.SetProgressReporter(ProgressReporterFunc(func(event *ProgressEvent) {
+		events = append(events, event)
+	}))
+
+	if err := manager.InstallPackage(&PackageSpec{Name: "requests"}); err != nil {
+		t.Fatalf("InstallPackage() error: %v", err)
+	}
+
+	want := []ProgressEventType{ProgressCollecting, ProgressUsingCached, ProgressInstalling, ProgressInstalled}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, eventType := range want {
+		if events[i].Type != eventType {
+			t.Errorf("events[%d].Type = %s, want %s", i, events[i].Type, eventType)
+		}
+	}
+
+	if got := ParseProgressOutput("Collecting a\r\nCollecting b\n"); len(got) != 2 {
+		t.Errorf("ParseProgressOutput() returned %d events, want 2", len(got))
+	}
+}
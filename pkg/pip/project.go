@@ -37,7 +37,7 @@ func (m *Manager) InitProject(path string, opts *ProjectOptions) error {
 	m.logInfo("Initializing project: %s", path)
 
 	// Create project directory if it doesn't exist
-	if err := os.MkdirAll(path, 0755); err != nil {
+	if err := m.mkdirAll(path, 0755); err != nil {
 		return &PipError{
 			Type:    "project_creation_failed",
 			Message: fmt.Sprintf("failed to create project directory: %v", err),
@@ -148,12 +148,12 @@ func (pm *ProjectManager) createRequirementsFile(projectPath string, opts *Proje
 			devContent.WriteString(dep + "\n")
 		}
 
-		if err := os.WriteFile(devFilePath, []byte(devContent.String()), 0644); err != nil {
+		if err := pm.manager.writeFile(devFilePath, []byte(devContent.String()), 0644); err != nil {
 			return err
 		}
 	}
 
-	return os.WriteFile(filePath, []byte(content.String()), 0644)
+	return pm.manager.writeFile(filePath, []byte(content.String()), 0644)
 }
 
 // createSetupFile creates setup.py
@@ -207,7 +207,7 @@ setup(
 		return err
 	}
 
-	file, err := os.Create(filePath)
+	file, err := pm.manager.createFile(filePath)
 	if err != nil {
 		return err
 	}
@@ -277,7 +277,7 @@ python_functions = ["test_*"]
 		return err
 	}
 
-	file, err := os.Create(filePath)
+	file, err := pm.manager.createFile(filePath)
 	if err != nil {
 		return err
 	}
@@ -337,7 +337,7 @@ This project is licensed under the {{.License}} License.
 		return err
 	}
 
-	file, err := os.Create(filePath)
+	file, err := pm.manager.createFile(filePath)
 	if err != nil {
 		return err
 	}
@@ -467,7 +467,7 @@ dmypy.json
 Thumbs.db
 `
 
-	return os.WriteFile(filePath, []byte(content), 0644)
+	return pm.manager.writeFile(filePath, []byte(content), 0644)
 }
 
 // createPackageDirectory creates the main package directory
@@ -475,7 +475,7 @@ func (pm *ProjectManager) createPackageDirectory(projectPath string, opts *Proje
 	packagePath := filepath.Join(projectPath, opts.Name)
 
 	// Create package directory
-	if err := os.MkdirAll(packagePath, 0755); err != nil {
+	if err := pm.manager.mkdirAll(packagePath, 0755); err != nil {
 		return err
 	}
 
@@ -493,7 +493,7 @@ __email__ = "{{.AuthorEmail}}"
 		return err
 	}
 
-	file, err := os.Create(initFile)
+	file, err := pm.manager.createFile(initFile)
 	if err != nil {
 		return err
 	}
@@ -520,7 +520,7 @@ if __name__ == "__main__":
 		return err
 	}
 
-	file, err = os.Create(mainFile)
+	file, err = pm.manager.createFile(mainFile)
 	if err != nil {
 		return err
 	}
@@ -536,12 +536,12 @@ func (pm *ProjectManager) createExtraFiles(projectPath string, opts *ProjectOpti
 
 		// Create directory if needed
 		if dir := filepath.Dir(filePath); dir != "." {
-			if err := os.MkdirAll(dir, 0755); err != nil {
+			if err := pm.manager.mkdirAll(dir, 0755); err != nil {
 				return err
 			}
 		}
 
-		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		if err := pm.manager.writeFile(filePath, []byte(content), 0644); err != nil {
 			return err
 		}
 	}
@@ -551,6 +551,21 @@ func (pm *ProjectManager) createExtraFiles(projectPath string, opts *ProjectOpti
 
 // InstallRequirements installs packages from requirements.txt
 func (m *Manager) InstallRequirements(path string) error {
+	if err := m.validateRequirementsFile(path); err != nil {
+		return err
+	}
+	if err := m.checkWheelhouse(nil, []string{path}); err != nil {
+		return err
+	}
+
+	m.logInfo("Installing requirements from: %s", path)
+
+	_, err := m.runInstallStream(nil, []string{"install", "-r", path}, nil)
+	return err
+}
+
+// validateRequirementsFile checks that a requirements file path is usable
+func (m *Manager) validateRequirementsFile(path string) error {
 	if path == "" {
 		return &PipError{
 			Type:    "invalid_path",
@@ -558,8 +573,6 @@ func (m *Manager) InstallRequirements(path string) error {
 		}
 	}
 
-	m.logInfo("Installing requirements from: %s", path)
-
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return &PipError{
@@ -568,13 +581,7 @@ func (m *Manager) InstallRequirements(path string) error {
 		}
 	}
 
-	pipPath, err := m.findPipExecutable()
-	if err != nil {
-		return ErrPipNotInstalled
-	}
-
-	args := []string{"install", "-r", path}
-	return m.executePipCommand(pipPath, args)
+	return nil
 }
 
 // GenerateRequirements generates requirements.txt file
@@ -0,0 +1,215 @@
+package pip
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// InstallReport is pip's installation report (pip 22.2+, "--report")
+type InstallReport struct {
+	Version     string               `json:"version"`
+	PipVersion  string               `json:"pip_version"`
+	Install     []*InstallReportItem `json:"install"`
+	Environment map[string]string    `json:"environment,omitempty"` // marker values, e.g. "python_version"
+}
+
+// InstallReportItem is one resolved distribution in an InstallReport
+type InstallReportItem struct {
+	Metadata        *ReportMetadata `json:"metadata"`
+	DownloadInfo    *DownloadInfo   `json:"download_info"`
+	IsDirect        bool            `json:"is_direct"` // requested as a URL or path
+	IsYanked        bool            `json:"is_yanked,omitempty"`
+	Requested       bool            `json:"requested"` // named by the user rather than pulled in as a dependency
+	RequestedExtras []string        `json:"requested_extras,omitempty"`
+}
+
+// ReportMetadata is the core metadata of a distribution (PEP 566 JSON form)
+type ReportMetadata struct {
+	MetadataVersion string   `json:"metadata_version"`
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	Summary         string   `json:"summary,omitempty"`
+	HomePage        string   `json:"home_page,omitempty"`
+	Author          string   `json:"author,omitempty"`
+	AuthorEmail     string   `json:"author_email,omitempty"`
+	License         string   `json:"license,omitempty"`
+	RequiresPython  string   `json:"requires_python,omitempty"`
+	RequiresDist    []string `json:"requires_dist,omitempty"`
+	ProvidesExtra   []string `json:"provides_extra,omitempty"`
+}
+
+// DownloadInfo records where a distribution came from (PEP 610). Exactly
+// one of ArchiveInfo, VCSInfo and DirInfo is set.
+type DownloadInfo struct {
+	URL          string       `json:"url"`
+	Subdirectory string       `json:"subdirectory,omitempty"`
+	ArchiveInfo  *ArchiveInfo `json:"archive_info,omitempty"`
+	VCSInfo      *VCSInfo     `json:"vcs_info,omitempty"`
+	DirInfo      *DirInfo     `json:"dir_info,omitempty"`
+}
+
+// ArchiveInfo describes a wheel or sdist downloaded from a URL or index
+type ArchiveInfo struct {
+	Hash   string            `json:"hash,omitempty"`   // legacy "<algorithm>=<digest>" form
+	Hashes map[string]string `json:"hashes,omitempty"` // by algorithm
+}
+
+// SHA256 returns the archive's sha256 digest, or "" when pip did not record one
+func (a *ArchiveInfo) SHA256() string {
+	if digest := a.Hashes["sha256"]; digest != "" {
+		return digest
+	}
+	if algorithm, digest := hashFragment(a.Hash); algorithm == "sha256" {
+		return digest
+	}
+	return ""
+}
+
+// VCSInfo describes a distribution installed from version control
+type VCSInfo struct {
+	VCS               string `json:"vcs"` // "git", "hg", "bzr" or "svn"
+	CommitID          string `json:"commit_id"`
+	RequestedRevision string `json:"requested_revision,omitempty"`
+}
+
+// DirInfo describes a distribution installed from a local directory
+type DirInfo struct {
+	Editable bool `json:"editable,omitempty"`
+}
+
+// Name returns the distribution's project name
+func (i *InstallReportItem) Name() string {
+	if i.Metadata == nil {
+		return ""
+	}
+	return i.Metadata.Name
+}
+
+// Version returns the distribution's version
+func (i *InstallReportItem) Version() string {
+	if i.Metadata == nil {
+		return ""
+	}
+	return i.Metadata.Version
+}
+
+// Find returns the item for a project, compared as described in PEP 503,
+// or nil when the report does not include it
+func (r *InstallReport) Find(name string) *InstallReportItem {
+	key := normalizePackageName(name)
+	for _, item := range r.Install {
+		if normalizePackageName(item.Name()) == key {
+			return item
+		}
+	}
+	return nil
+}
+
+// InstallPackageWithReport installs a package like InstallPackage and
+// returns pip's installation report. It requires pip 22.2 or newer. In
+// dry-run mode the install is planned and the report is empty.
+func (m *Manager) InstallPackageWithReport(pkg *PackageSpec) (*InstallReport, error) {
+	if err := m.validatePackageSpec(pkg); err != nil {
+		return nil, err
+	}
+	if err := m.checkWheelhouse([]*PackageSpec{pkg}, nil); err != nil {
+		return nil, err
+	}
+
+	m.logInfo("Installing package with report: %s", pkg.Name)
+
+	return m.installWithReport(pkg, m.buildInstallArgs(pkg))
+}
+
+// InstallRequirementsWithReport installs a requirements file like
+// InstallRequirements and returns pip's installation report. It requires
+// pip 22.2 or newer. In dry-run mode the install is planned and the report
+// is empty.
+func (m *Manager) InstallRequirementsWithReport(path string) (*InstallReport, error) {
+	if err := m.validateRequirementsFile(path); err != nil {
+		return nil, err
+	}
+	if err := m.checkWheelhouse(nil, []string{path}); err != nil {
+		return nil, err
+	}
+
+	m.logInfo("Installing requirements with report from: %s", path)
+
+	return m.installWithReport(nil, []string{"install", "-r", path})
+}
+
+// installWithReport runs a pip install command with --report and decodes
+// the report. In dry-run mode the command is planned and an empty report is
+// returned.
+func (m *Manager) installWithReport(pkg *PackageSpec, args []string) (*InstallReport, error) {
+	reportPath, cleanup, err := reportFile()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	result, err := m.runInstallStream(pkg, append(args, "--report", reportPath), nil)
+	if err != nil {
+		return nil, reportOptionError(err, result.OutputLines)
+	}
+	if m.dryRun() {
+		return &InstallReport{}, nil
+	}
+	return readInstallReport(reportPath)
+}
+
+// reportFile returns a path for pip to write a report to and a function
+// that removes it
+func reportFile() (string, func(), error) {
+	file, err := os.CreateTemp("", "pip-report-*.json")
+	if err != nil {
+		return "", nil, &PipError{
+			Type:    string(ErrorTypePermissionDenied),
+			Message: fmt.Sprintf("failed to create report file: %v", err),
+		}
+	}
+	file.Close()
+
+	path := file.Name()
+	return path, func() { os.Remove(path) }, nil
+}
+
+// reportOptionError replaces the error of a pip too old to know --report
+// or --dry-run with a clearer one
+func reportOptionError(err error, lines []string) error {
+	for _, line := range lines {
+		if strings.Contains(line, "no such option: --report") || strings.Contains(line, "no such option: --dry-run") {
+			return &PipError{
+				Type:    string(ErrorTypeFeatureDisabled),
+				Message: "install reports and previews require pip 22.2 or newer",
+			}
+		}
+	}
+	return err
+}
+
+// readInstallReport decodes a report written by pip
+func readInstallReport(path string) (*InstallReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypeFileNotFound),
+			Message: fmt.Sprintf("failed to read install report: %v", err),
+		}
+	}
+	return parseInstallReport(data)
+}
+
+// parseInstallReport decodes an installation report
+func parseInstallReport(data []byte) (*InstallReport, error) {
+	var report InstallReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypeCommandFailed),
+			Message: fmt.Sprintf("invalid install report: %v", err),
+		}
+	}
+	return &report, nil
+}
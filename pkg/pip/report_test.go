@@ -0,0 +1,161 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testInstallReport = `{
+  "version": "1",
+  "pip_version": "23.3.1",
+  "install": [
+    {
+      "download_info": {
+        "url": "https://files.pythonhosted.org/packages/requests-2.31.0-py3-none-any.whl",
+        "archive_info": {"hash": "sha256=58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f", "hashes": {"sha256": "58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f"}}
+      },
+      "is_direct": false,
+      "requested": true,
+      "requested_extras": ["socks"],
+      "metadata": {"metadata_version": "2.1", "name": "requests", "version": "2.31.0", "requires_python": ">=3.7", "requires_dist": ["idna<4,>=2.5", "PySocks!=1.5.7,>=1.5.6; extra == \"socks\""]}
+    },
+    {
+      "download_info": {"url": "https://github.com/example/mylib.git", "vcs_info": {"vcs": "git", "commit_id": "1f2e3d4c", "requested_revision": "v1.2"}},
+      "is_direct": true,
+      "requested": true,
+      "metadata": {"metadata_version": "2.1", "name": "mylib", "version": "1.2.0"}
+    },
+    {
+      "download_info": {"url": "https://files.pythonhosted.org/packages/idna-3.6-py3-none-any.whl", "archive_info": {"hash": "sha256=c05567e9c24a6b9faaa835c4821bad0590fbb9d5779e7caa6e1cc4978e7eb24f"}},
+      "is_direct": false,
+      "requested": false,
+      "metadata": {"metadata_version": "2.1", "name": "idna", "version": "3.6"}
+    }
+  ],
+  "environment": {"python_version": "3.11", "sys_platform": "linux"}
+}`
+
+// writeReport makes a fake pip run write report to its --report path
+func writeReport(t *testing.T, report string) func(cmd *Command) {
+	return func(cmd *Command) {
+		for i, arg := range cmd.Args {
+			if arg == "--report" && i+1 < len(cmd.Args) {
+				if err := os.WriteFile(cmd.Args[i+1], []byte(report), 0644); err != nil {
+					t.Errorf("writing report: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func TestInstallPackageWithReport(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests[socks]==2.31.0", "--report", "*").
+		Do(writeReport(t, testInstallReport)).
+		Respond("Successfully installed idna-3.6 requests-2.31.0\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	report, err := manager.InstallPackageWithReport(&PackageSpec{Name: "requests", Version: "==2.31.0", Extras: []string{"socks"}})
+	if err != nil {
+		t.Fatalf("InstallPackageWithReport() error: %v", err)
+	}
+
+	if report.PipVersion != "23.3.1" || len(report.Install) != 3 || report.Environment["sys_platform"] != "linux" {
+		t.Fatalf("report = %+v", report)
+	}
+
+	requests := report.Find("Requests")
+	if requests == nil || requests.Version() != "2.31.0" || !requests.Requested || requests.RequestedExtras[0] != "socks" {
+		t.Fatalf("requests = %+v", requests)
+	}
+	if sha := requests.DownloadInfo.ArchiveInfo.SHA256(); sha != "58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f" {
+		t.Errorf("requests sha256 = %q", sha)
+	}
+	if len(requests.Metadata.RequiresDist) != 2 || requests.Metadata.RequiresPython != ">=3.7" {
+		t.Errorf("requests metadata = %+v", requests.Metadata)
+	}
+
+	mylib := report.Find("mylib")
+	if mylib == nil || !mylib.IsDirect || mylib.DownloadInfo.VCSInfo == nil || mylib.DownloadInfo.VCSInfo.CommitID != "1f2e3d4c" {
+		t.Errorf("mylib = %+v", mylib)
+	}
+
+	idna := report.Find("idna")
+	if idna == nil || idna.Requested || idna.DownloadInfo.ArchiveInfo.SHA256() != "c05567e9c24a6b9faaa835c4821bad0590fbb9d5779e7caa6e1cc4978e7eb24f" {
+		t.Errorf("idna = %+v", idna)
+	}
+	if report.Find("numpy") != nil {
+		t.Error("Find(numpy) should be nil")
+	}
+
+	// The report file is removed afterwards
+	reportPath := runner.Calls()[0].Args[len(runner.Calls()[0].Args)-1]
+	if _, err := os.Stat(reportPath); !os.IsNotExist(err) {
+		t.Errorf("report file %s was not removed", reportPath)
+	}
+}
+
+func TestInstallRequirementsWithReport(t *testing.T) {
+	requirements := filepath.Join(t.TempDir(), "requirements.txt")
+	if err := os.WriteFile(requirements, []byte("requests==2.31.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "-r", requirements, "--report", "*").
+		Do(writeReport(t, testInstallReport)).
+		Respond("", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	report, err := manager.InstallRequirementsWithReport(requirements)
+	if err != nil {
+		t.Fatalf("InstallRequirementsWithReport() error: %v", err)
+	}
+	if len(report.Install) != 3 {
+		t.Errorf("got %d items, want 3", len(report.Install))
+	}
+}
+
+func TestInstallWithReportOldPip(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "...").Respond("",
+		"\nUsage:\n  python -m pip install [options] <requirement specifier> ...\n\nno such option: --report\n", 2)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.InstallPackageWithReport(&PackageSpec{Name: "six"}); !IsErrorType(err, ErrorTypeFeatureDisabled) {
+		t.Errorf("error = %v, want feature_disabled", err)
+	}
+}
+
+func TestInstallWithReportDryRun(t *testing.T) {
+	runner := NewFakeRunner()
+	manager := NewManager(&Config{DryRun: true})
+	manager.SetCommandRunner(runner)
+
+	report, err := manager.InstallPackageWithReport(&PackageSpec{Name: "six"})
+	if err != nil {
+		t.Fatalf("InstallPackageWithReport() error: %v", err)
+	}
+	if report == nil || len(report.Install) != 0 || report.Find("six") != nil {
+		t.Errorf("report = %+v, want an empty report", report)
+	}
+	if len(runner.Calls()) != 0 || len(manager.Plan().Commands()) != 1 {
+		t.Errorf("install should be planned, not run")
+	}
+}
+
+func TestArchiveInfoSHA256(t *testing.T) {
+	if got := (&ArchiveInfo{Hash: "sha256=abc"}).SHA256(); got != "abc" {
+		t.Errorf("legacy hash = %q", got)
+	}
+	if got := (&ArchiveInfo{Hash: "md5=abc"}).SHA256(); got != "" {
+		t.Errorf("md5 hash = %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package pip
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileRequirement is a named requirement from a requirements file
+type fileRequirement struct {
+	name      string // normalized
+	specifier string // e.g. ">=1.0,<2"
+	marker    string // environment marker, e.g. "python_version < '3.8'"
+	direct    bool   // "name @ url"
+}
+
+// readRequirements returns the named requirements in a requirements file,
+// following nested "-r" includes and line continuations
+func readRequirements(path string, visited map[string]bool) ([]*fileRequirement, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if visited[abs] {
+		return nil, nil
+	}
+	visited[abs] = true
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var requirements []*fileRequirement
+	var continued string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(line, "\\") {
+			continued += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		line, continued = strings.TrimSpace(continued+line), ""
+
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "-") {
+			fields := strings.Fields(strings.Replace(line, "=", " ", 1))
+			if len(fields) == 2 && (fields[0] == "-r" || fields[0] == "--requirement") {
+				nested := fields[1]
+				if !filepath.IsAbs(nested) {
+					nested = filepath.Join(filepath.Dir(path), nested)
+				}
+				included, err := readRequirements(nested, visited)
+				if err != nil {
+					return nil, err
+				}
+				requirements = append(requirements, included...)
+			}
+			continue
+		}
+
+		if req := parseRequirementLine(line); req != nil {
+			requirements = append(requirements, req)
+		}
+	}
+
+	return requirements, scanner.Err()
+}
+
+// parseRequirementLine parses a requirement such as
+// "requests[socks]>=2.0 ; python_version >= '3.8' --hash=sha256:...".
+// It returns nil for bare URLs and local paths, which name no package.
+func parseRequirementLine(line string) *fileRequirement {
+	// Per-requirement options such as --hash follow the requirement
+	if idx := strings.Index(line, " --"); idx >= 0 {
+		line = strings.TrimSpace(line[:idx])
+	}
+
+	name := requirementNameRegex.FindString(line)
+	rest := strings.TrimSpace(line[len(name):])
+
+	// "name @ url" names its package; bare URLs and paths do not
+	if strings.HasPrefix(rest, "@") {
+		return &fileRequirement{name: normalizePackageName(name), direct: true}
+	}
+	if name == "" || strings.Contains(line, "://") || strings.ContainsAny(line, `/\`) || strings.HasPrefix(line, ".") {
+		return nil
+	}
+
+	req := &fileRequirement{name: normalizePackageName(name)}
+	if idx := strings.Index(rest, ";"); idx >= 0 {
+		req.marker = strings.TrimSpace(rest[idx+1:])
+		rest = rest[:idx]
+	}
+	if strings.HasPrefix(rest, "[") {
+		if idx := strings.Index(rest, "]"); idx >= 0 {
+			rest = rest[idx+1:]
+		}
+	}
+	req.specifier = strings.Join(strings.Fields(rest), "")
+	return req
+}
@@ -0,0 +1,53 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRequirementLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want *fileRequirement
+	}{
+		{"requests[socks] >= 2.0, <3", &fileRequirement{name: "requests", specifier: ">=2.0,<3"}},
+		{"Zope.Interface==6.0 --hash=sha256:abc", &fileRequirement{name: "zope-interface", specifier: "==6.0"}},
+		{"pywin32==306 ; sys_platform == 'win32'", &fileRequirement{name: "pywin32", specifier: "==306", marker: "sys_platform == 'win32'"}},
+		{"mylib @ https://example.com/mylib.zip", &fileRequirement{name: "mylib", direct: true}},
+		{"https://example.com/pkg-1.0.tar.gz", nil},
+		{"./vendor/pkg", nil},
+	}
+
+	for _, tt := range tests {
+		got := parseRequirementLine(tt.line)
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("parseRequirementLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestReadRequirements(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte("six==1.16.0\n-r requirements.txt\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte(
+		"# pinned\n-r base.txt\nrequests>=2.0 \\\n    --hash=sha256:abc # comment\n-e ./local\n"), 0644)
+
+	requirements, err := readRequirements(filepath.Join(dir, "requirements.txt"), make(map[string]bool))
+	if err != nil {
+		t.Fatalf("readRequirements() error: %v", err)
+	}
+
+	var got []string
+	for _, req := range requirements {
+		got = append(got, req.name+req.specifier)
+	}
+	if want := []string{"six==1.16.0", "requests>=2.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requirements = %v, want %v", got, want)
+	}
+
+	if _, err := readRequirements(filepath.Join(dir, "missing.txt"), make(map[string]bool)); err == nil {
+		t.Error("readRequirements() should fail for a missing file")
+	}
+}
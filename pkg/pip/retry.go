@@ -0,0 +1,63 @@
+package pip
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetryDelay is used when Config.RetryDelay is not set
+	defaultRetryDelay = time.Second
+
+	// maxRetryDelay caps the exponential backoff between attempts
+	maxRetryDelay = 30 * time.Second
+)
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// retryDelay returns the backoff before retry number attempt (zero based).
+// The delay doubles with every attempt and is jittered to between half and
+// the full computed value so concurrent clients do not retry in lockstep.
+func (m *Manager) retryDelay(attempt int) time.Duration {
+	base := m.currentConfig().RetryDelay
+	if base <= 0 {
+		base = defaultRetryDelay
+	}
+
+	delay := base
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+
+	jitterMu.Lock()
+	jitter := jitterRand.Int63n(half + 1)
+	jitterMu.Unlock()
+
+	return time.Duration(half + jitter)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+package pip
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingRunner blocks every command until its context is done
+type blockingRunner struct{}
+
+func (blockingRunner) Run(ctx context.Context, cmd *Command) (*CommandResult, error) {
+	<-ctx.Done()
+	return &CommandResult{ExitCode: -1}, ctx.Err()
+}
+
+func (blockingRunner) LookPath(file string) (string, error) {
+	return file, nil
+}
+
+func newRetryTestManager(runner CommandRunner, retries int) *Manager {
+	config := DefaultConfig()
+	config.Retries = retries
+	config.RetryDelay = time.Millisecond
+
+	manager := NewManager(config)
+	manager.SetCommandRunner(runner)
+	return manager
+}
+
+func TestRetryTransientFailure(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests").Once().Respond("", "ERROR: HTTP error 503 while getting https://pypi.org/simple/requests/\n", 1)
+	runner.On("python", "-m", "pip", "install", "requests").Once().Respond("", "Connection reset by peer\n", 1)
+	runner.On("python", "-m", "pip", "install", "requests").Respond("Successfully installed requests-2.31.0\n", "", 0)
+
+	manager := newRetryTestManager(runner, 3)
+
+	if err := manager.InstallPackage(&PackageSpec{Name: "requests"}); err != nil {
+		t.Fatalf("InstallPackage() error: %v", err)
+	}
+	if calls := len(runner.Calls()); calls != 3 {
+		t.Errorf("runner called %d times, want 3", calls)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests").Respond("", "Temporary failure in name resolution\n", 1)
+
+	manager := newRetryTestManager(runner, 2)
+
+	err := manager.InstallPackage(&PackageSpec{Name: "requests"})
+	if !IsErrorType(err, ErrorTypeNetworkError) {
+		t.Errorf("InstallPackage() error type = %q, want %q", GetErrorType(err), ErrorTypeNetworkError)
+	}
+	if calls := len(runner.Calls()); calls != 3 {
+		t.Errorf("runner called %d times, want 3", calls)
+	}
+}
+
+func TestNoRetryForDeterministicFailure(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "nonexistent").Respond("", "ERROR: No matching distribution found for nonexistent\n", 1)
+
+	manager := newRetryTestManager(runner, 3)
+
+	err := manager.InstallPackage(&PackageSpec{Name: "nonexistent"})
+	if !IsErrorType(err, ErrorTypeCommandFailed) {
+		t.Errorf("InstallPackage() error type = %q, want %q", GetErrorType(err), ErrorTypeCommandFailed)
+	}
+	if calls := len(runner.Calls()); calls != 1 {
+		t.Errorf("runner called %d times, want 1", calls)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	manager := newRetryTestManager(blockingRunner{}, 1)
+	manager.GetConfig().Timeout = 20 * time.Millisecond
+
+	start := time.Now()
+	_, err := manager.executePipCommandWithOutput("pip", []string{"install", "requests"})
+	if !IsErrorType(err, ErrorTypeTimeout) {
+		t.Fatalf("error type = %q, want %q (err: %v)", GetErrorType(err), ErrorTypeTimeout, err)
+	}
+
+	// One retry means two timed out attempts
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("elapsed %v, expected both attempts to time out", elapsed)
+	}
+}
+
+func TestNoRetryAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := NewFakeRunner()
+	manager := newRetryTestManager(runner, 3)
+	manager.SetContext(ctx)
+
+	if _, err := manager.executePipCommandWithOutput("pip", []string{"list"}); err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+	if calls := len(runner.Calls()); calls != 1 {
+		t.Errorf("runner called %d times, want 1", calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	manager := NewManager(&Config{RetryDelay: 100 * time.Millisecond})
+
+	for attempt, full := range []time.Duration{100, 200, 400, 800} {
+		full *= time.Millisecond
+		delay := manager.retryDelay(attempt)
+		if delay < full/2 || delay > full {
+			t.Errorf("retryDelay(%d) = %v, want within [%v, %v]", attempt, delay, full/2, full)
+		}
+	}
+
+	if delay := manager.retryDelay(20); delay > maxRetryDelay {
+		t.Errorf("retryDelay(20) = %v, want at most %v", delay, maxRetryDelay)
+	}
+
+	manager.GetConfig().RetryDelay = 0
+	if delay := manager.retryDelay(0); delay < defaultRetryDelay/2 || delay > defaultRetryDelay {
+		t.Errorf("retryDelay(0) with default base = %v", delay)
+	}
+}
+
+func TestRetryKeepsOnlyFinalAttemptOutput(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests", "click").Once().Respond("",
+		"WARNING: Retrying (Retry(total=0)) after connection broken by 'NewConnectionError(Connection reset by peer)': /simple/click/\n"+
+			"ERROR: Could not find a version that satisfies the requirement click (from versions: none)\n"+
+			"ERROR: No matching distribution found for click\n", 1)
+	runner.On("python", "-m", "pip", "install", "requests", "click").Respond(
+		"Installing collected packages: requests, click\nSuccessfully installed click-8.1.7 requests-2.31.0\n", "", 0)
+
+	manager := newRetryTestManager(runner, 1)
+
+	results, err := manager.InstallPackages([]*PackageSpec{{Name: "requests"}, {Name: "click"}}, nil)
+	if err != nil {
+		t.Fatalf("InstallPackages() error: %v", err)
+	}
+	if calls := len(runner.Calls()); calls != 2 {
+		t.Fatalf("runner called %d times, want 2", calls)
+	}
+	for _, result := range results {
+		if !result.Success || result.Error != nil {
+			t.Errorf("%s: success = %v, error = %v", result.Package.Name, result.Success, result.Error)
+		}
+		for _, line := range result.OutputLines {
+			if strings.Contains(line, "No matching distribution") {
+				t.Errorf("%s output includes the failed attempt: %q", result.Package.Name, line)
+			}
+		}
+	}
+}
+
+func TestRetrySkipsBuildFailureMentioningTimeout(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "mypkg").Respond("",
+		"  Building wheel for mypkg (pyproject.toml): finished with status 'error'\n"+
+			"  RuntimeError: probe for libfoo timed out after 30 seconds\n"+
+			"  error: subprocess-exited-with-error\n"+
+			"ERROR: Could not build wheels for mypkg, which is required to install pyproject.toml-based projects\n", 1)
+
+	manager := newRetryTestManager(runner, 3)
+
+	if err := manager.InstallPackage(&PackageSpec{Name: "mypkg"}); err == nil || IsTransientError(err) {
+		t.Errorf("InstallPackage() error = %v, want a non-transient failure", err)
+	}
+	if calls := len(runner.Calls()); calls != 1 {
+		t.Errorf("runner called %d times, want 1", calls)
+	}
+}
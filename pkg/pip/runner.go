@@ -0,0 +1,579 @@
+package pip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Command describes a subprocess to be executed by a CommandRunner
+type Command struct {
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
+	Env  []string `json:"-"` // nil means inherit the parent environment
+	Dir  string   `json:"dir,omitempty"`
+
+	// Limits are applied to the process and its children by ExecRunner
+	Limits ResourceLimits `json:"-"`
+	// KillGracePeriod is the delay between SIGTERM and SIGKILL on cancellation
+	KillGracePeriod time.Duration `json:"-"`
+
+	// Stdout and Stderr optionally receive output while the command runs.
+	// Output is still captured in the CommandResult when they are set.
+	Stdout io.Writer `json:"-"`
+	Stderr io.Writer `json:"-"`
+}
+
+// String returns the command line as a single string
+func (c *Command) String() string {
+	return strings.Join(c.Argv(), " ")
+}
+
+// Argv returns the full argument vector including the command name
+func (c *Command) Argv() []string {
+	return append([]string{c.Name}, c.Args...)
+}
+
+// CommandResult holds the outcome of a subprocess execution
+type CommandResult struct {
+	Stdout   string        `json:"stdout,omitempty"`
+	Stderr   string        `json:"stderr,omitempty"`
+	Output   string        `json:"output,omitempty"` // stdout and stderr interleaved
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// CommandRunner executes subprocesses on behalf of a Manager.
+//
+// Run returns a non-nil error when the command could not be started or
+// exited with a non-zero status; the result is populated in both cases.
+// ExitCode is -1 when the process never ran.
+type CommandRunner interface {
+	Run(ctx context.Context, cmd *Command) (*CommandResult, error)
+	LookPath(file string) (string, error)
+}
+
+// defaultKillGracePeriod is used when Command.KillGracePeriod is not set
+const defaultKillGracePeriod = 5 * time.Second
+
+// ExecRunner runs commands using os/exec
+type ExecRunner struct{}
+
+// NewExecRunner creates a runner backed by os/exec
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{}
+}
+
+// Run executes the command and captures its output.
+//
+// The command is started in its own process group. When ctx is done the
+// whole group receives SIGTERM and, if it has not exited after the grace
+// period, SIGKILL, so build subprocesses spawned by pip do not outlive it.
+func (r *ExecRunner) Run(ctx context.Context, c *Command) (*CommandResult, error) {
+	name, args := limitCommand(c.Name, c.Args, c.Limits)
+	cmd := exec.Command(name, args...)
+	cmd.Env = c.Env
+	cmd.Dir = c.Dir
+	setProcessGroup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	combined := &syncBuffer{}
+	cmd.Stdout = &teeWriter{primary: &stdout, combined: combined, sink: c.Stdout}
+	cmd.Stderr = &teeWriter{primary: &stderr, combined: combined, sink: c.Stderr}
+
+	grace := c.KillGracePeriod
+	if grace <= 0 {
+		grace = defaultKillGracePeriod
+	}
+
+	start := time.Now()
+	err := ctx.Err()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err == nil {
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				terminateProcessGroup(cmd.Process, grace, done)
+			case <-done:
+			}
+		}()
+
+		err = cmd.Wait()
+		close(done)
+
+		if err != nil && ctx.Err() != nil {
+			err = fmt.Errorf("%v: %w", err, ctx.Err())
+		}
+	}
+
+	result := &CommandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Output:   combined.String(),
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			result.ExitCode = exitError.ExitCode()
+		} else {
+			result.ExitCode = -1
+		}
+	}
+
+	return result, err
+}
+
+// LookPath searches for an executable in PATH
+func (r *ExecRunner) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writers
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// teeWriter writes to a per-stream buffer, the shared combined buffer and
+// an optional caller-provided sink
+type teeWriter struct {
+	primary  *bytes.Buffer
+	combined *syncBuffer
+	sink     io.Writer
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.primary.Write(p)
+	if w.sink != nil {
+		w.sink.Write(p)
+	}
+	return w.combined.Write(p)
+}
+
+// writeStreams replays canned output to the command's streaming sinks
+func writeStreams(cmd *Command, stdout, stderr string) {
+	if cmd.Stdout != nil && stdout != "" {
+		io.WriteString(cmd.Stdout, stdout)
+	}
+	if cmd.Stderr != nil && stderr != "" {
+		io.WriteString(cmd.Stderr, stderr)
+	}
+}
+
+// FakeResponse is a canned response returned by FakeRunner for matching commands
+type FakeResponse struct {
+	pattern  []string
+	stdout   string
+	stderr   string
+	exitCode int
+	err      error
+	action   func(cmd *Command)
+	once     bool
+	used     bool
+}
+
+// Respond sets the output and exit code returned for matching commands
+func (r *FakeResponse) Respond(stdout, stderr string, exitCode int) *FakeResponse {
+	r.stdout = stdout
+	r.stderr = stderr
+	r.exitCode = exitCode
+	return r
+}
+
+// Fail makes matching commands fail to start with the given error
+func (r *FakeResponse) Fail(err error) *FakeResponse {
+	r.err = err
+	r.exitCode = -1
+	return r
+}
+
+// Do runs fn with each matching command before responding, for side
+// effects such as writing a file the real command would create
+func (r *FakeResponse) Do(fn func(cmd *Command)) *FakeResponse {
+	r.action = fn
+	return r
+}
+
+// Once makes the response match a single command only, so successive
+// registrations for the same pattern are served in order
+func (r *FakeResponse) Once() *FakeResponse {
+	r.once = true
+	return r
+}
+
+// FakeRunner is a scripted CommandRunner for tests.
+//
+// Responses are registered with On and matched against the full argv in
+// registration order. Each pattern element matches one argument exactly, by
+// glob (see filepath.Match), or "*" for any value. A trailing "..." matches
+// any remaining arguments. The first element is also matched against the
+// base name of the executable so "pip" matches "/usr/bin/pip".
+type FakeRunner struct {
+	// Paths maps executable names to LookPath results. When nil every
+	// lookup succeeds and returns the name unchanged.
+	Paths map[string]string
+
+	mu        sync.Mutex
+	responses []*FakeResponse
+	calls     []*Command
+}
+
+// NewFakeRunner creates an empty scripted runner
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// On registers a response for commands matching the given argv pattern
+func (f *FakeRunner) On(pattern ...string) *FakeResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resp := &FakeResponse{pattern: pattern}
+	f.responses = append(f.responses, resp)
+	return resp
+}
+
+// Calls returns every command the runner has received
+func (f *FakeRunner) Calls() []*Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]*Command, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// Run returns the first registered response matching the command
+func (f *FakeRunner) Run(ctx context.Context, cmd *Command) (*CommandResult, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, cmd)
+	resp := f.match(cmd.Argv())
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return &CommandResult{ExitCode: -1}, err
+	}
+
+	if resp == nil {
+		return &CommandResult{ExitCode: -1}, fmt.Errorf("fake runner: no response for %q", cmd.String())
+	}
+	if resp.action != nil {
+		resp.action(cmd)
+	}
+
+	result := &CommandResult{
+		Stdout:   resp.stdout,
+		Stderr:   resp.stderr,
+		Output:   resp.stdout + resp.stderr,
+		ExitCode: resp.exitCode,
+	}
+	writeStreams(cmd, resp.stdout, resp.stderr)
+
+	if resp.err != nil {
+		return result, resp.err
+	}
+	if resp.exitCode != 0 {
+		return result, fmt.Errorf("exit status %d", resp.exitCode)
+	}
+
+	return result, nil
+}
+
+// LookPath resolves an executable name using Paths
+func (f *FakeRunner) LookPath(file string) (string, error) {
+	if f.Paths == nil {
+		return file, nil
+	}
+	if path, ok := f.Paths[file]; ok {
+		return path, nil
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+// match finds the first response whose pattern matches argv
+func (f *FakeRunner) match(argv []string) *FakeResponse {
+	for _, resp := range f.responses {
+		if resp.used || !matchArgv(resp.pattern, argv) {
+			continue
+		}
+		if resp.once {
+			resp.used = true
+		}
+		return resp
+	}
+	return nil
+}
+
+// matchArgv reports whether argv satisfies the pattern
+func matchArgv(pattern, argv []string) bool {
+	for i, token := range pattern {
+		if token == "..." && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(argv) {
+			return false
+		}
+		if !matchArg(token, argv[i]) && !(i == 0 && matchArg(token, filepath.Base(argv[i]))) {
+			return false
+		}
+	}
+	return len(pattern) == len(argv)
+}
+
+// matchArg matches a single argument against a pattern token
+func matchArg(token, arg string) bool {
+	if token == "*" || token == arg {
+		return true
+	}
+	matched, err := filepath.Match(token, arg)
+	return err == nil && matched
+}
+
+// RecordedCommand is a single command and its result stored in a fixture file
+type RecordedCommand struct {
+	Command *Command       `json:"command"`
+	Result  *CommandResult `json:"result"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// RecordingRunner wraps another runner and saves every session to a fixture file
+type RecordingRunner struct {
+	runner  CommandRunner
+	path    string
+	mu      sync.Mutex
+	records []*RecordedCommand
+}
+
+// NewRecordingRunner creates a runner that records to the given fixture path
+func NewRecordingRunner(runner CommandRunner, path string) *RecordingRunner {
+	if runner == nil {
+		runner = NewExecRunner()
+	}
+	return &RecordingRunner{
+		runner: runner,
+		path:   path,
+	}
+}
+
+// Run executes the command with the wrapped runner and records the result
+func (r *RecordingRunner) Run(ctx context.Context, cmd *Command) (*CommandResult, error) {
+	result, err := r.runner.Run(ctx, cmd)
+
+	record := &RecordedCommand{Command: cmd, Result: result}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record)
+	if saveErr := r.save(); saveErr != nil {
+		return result, fmt.Errorf("failed to write fixture %s: %w", r.path, saveErr)
+	}
+
+	return result, err
+}
+
+// LookPath delegates to the wrapped runner
+func (r *RecordingRunner) LookPath(file string) (string, error) {
+	return r.runner.LookPath(file)
+}
+
+// save writes all records to the fixture file
+func (r *RecordingRunner) save() error {
+	data, err := json.MarshalIndent(r.records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(r.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(r.path, data, 0644)
+}
+
+// ReplayRunner serves commands from a fixture file written by RecordingRunner.
+//
+// Commands are matched by argv, comparing the executable by base name so
+// fixtures recorded on one machine replay on another. Each record is served
+// once, in the order it was recorded.
+type ReplayRunner struct {
+	mu      sync.Mutex
+	records []*RecordedCommand
+	used    []bool
+}
+
+// NewReplayRunner loads a fixture file for replay
+func NewReplayRunner(path string) (*ReplayRunner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*RecordedCommand
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	return &ReplayRunner{
+		records: records,
+		used:    make([]bool, len(records)),
+	}, nil
+}
+
+// Run returns the next unused recording matching the command
+func (r *ReplayRunner) Run(ctx context.Context, cmd *Command) (*CommandResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, record := range r.records {
+		if r.used[i] || record.Command == nil || !sameArgv(record.Command.Argv(), cmd.Argv()) {
+			continue
+		}
+		r.used[i] = true
+
+		result := record.Result
+		if result == nil {
+			result = &CommandResult{}
+		}
+		writeStreams(cmd, result.Stdout, result.Stderr)
+		if record.Error != "" {
+			return result, fmt.Errorf("%s", record.Error)
+		}
+		return result, nil
+	}
+
+	return &CommandResult{ExitCode: -1}, fmt.Errorf("replay runner: no recording for %q", cmd.String())
+}
+
+// LookPath resolves executables from the recorded commands
+func (r *ReplayRunner) LookPath(file string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, record := range r.records {
+		if record.Command != nil && filepath.Base(record.Command.Name) == filepath.Base(file) {
+			return record.Command.Name, nil
+		}
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+// sameArgv compares two argument vectors, matching the executable by base name
+func sameArgv(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if i == 0 {
+			if filepath.Base(a[i]) != filepath.Base(b[i]) {
+				return false
+			}
+			continue
+		}
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetCommandRunner sets the runner used for every subprocess
+func (m *Manager) SetCommandRunner(runner CommandRunner) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runner = runner
+}
+
+// GetCommandRunner returns the runner used for every subprocess
+func (m *Manager) GetCommandRunner() CommandRunner {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.runner == nil {
+		return NewExecRunner()
+	}
+	return m.runner
+}
+
+// runCommand executes a command through the configured runner, applying
+// Config.Timeout to the individual command
+func (m *Manager) runCommand(cmd *Command) (*CommandResult, error) {
+	m.logDebug("Executing command: %s", cmd.String())
+
+	if cmd.Env == nil {
+		env, err := m.buildEnvironment()
+		if err != nil {
+			return &CommandResult{ExitCode: -1}, err
+		}
+		cmd.Env = env
+	}
+
+	config := m.currentConfig()
+	if cmd.Limits == (ResourceLimits{}) {
+		cmd.Limits = config.ResourceLimits
+	}
+	if cmd.KillGracePeriod == 0 {
+		cmd.KillGracePeriod = config.KillGracePeriod
+	}
+
+	parent := m.context()
+	timeout := config.Timeout
+
+	ctx := parent
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result, err := m.GetCommandRunner().Run(ctx, cmd)
+	if result == nil {
+		result = &CommandResult{ExitCode: -1}
+	}
+
+	// Report per-command timeouts distinctly from caller cancellation
+	if err != nil && ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+		err = fmt.Errorf("command timed out after %v: %w", timeout, context.DeadlineExceeded)
+	}
+
+	m.recordJournal(cmd, result, err, start, time.Now())
+
+	return result, err
+}
+
+// lookPath resolves an executable through the configured runner
+func (m *Manager) lookPath(file string) (string, error) {
+	return m.GetCommandRunner().LookPath(file)
+}
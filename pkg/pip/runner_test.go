@@ -0,0 +1,242 @@
+package pip
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchArgv(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern []string
+		argv    []string
+		want    bool
+	}{
+		{"exact", []string{"pip", "list"}, []string{"pip", "list"}, true},
+		{"base name", []string{"pip", "list"}, []string{"/usr/bin/pip", "list"}, true},
+		{"wildcard", []string{"pip", "install", "*"}, []string{"pip", "install", "requests"}, true},
+		{"glob", []string{"pip", "install", "req*"}, []string{"pip", "install", "requests==2.0"}, true},
+		{"rest", []string{"pip", "install", "..."}, []string{"pip", "install", "a", "b", "c"}, true},
+		{"rest matches nothing", []string{"pip", "..."}, []string{"pip"}, true},
+		{"too short", []string{"pip", "install", "*"}, []string{"pip", "install"}, false},
+		{"too long", []string{"pip", "install"}, []string{"pip", "install", "requests"}, false},
+		{"mismatch", []string{"pip", "uninstall"}, []string{"pip", "install"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchArgv(tt.pattern, tt.argv); got != tt.want {
+				t.Errorf("matchArgv(%v, %v) = %v, want %v", tt.pattern, tt.argv, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeRunner(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("pip", "--version").Respond("pip 23.0 from /x (python 3.11)\n", "", 0)
+	runner.On("pip", "install", "...").Respond("", "ERROR: boom\n", 1)
+	runner.On("python", "...").Fail(errors.New("cannot start"))
+
+	ctx := context.Background()
+
+	result, err := runner.Run(ctx, &Command{Name: "pip", Args: []string{"--version"}})
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result.Stdout, "pip 23.0") {
+		t.Errorf("Stdout = %q", result.Stdout)
+	}
+
+	result, err = runner.Run(ctx, &Command{Name: "pip", Args: []string{"install", "requests"}})
+	if err == nil {
+		t.Error("Run() should fail for non-zero exit code")
+	}
+	if result.ExitCode != 1 || result.Stderr != "ERROR: boom\n" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	result, err = runner.Run(ctx, &Command{Name: "python", Args: []string{"-V"}})
+	if err == nil || result.ExitCode != -1 {
+		t.Errorf("Run() = %+v, %v; want start failure", result, err)
+	}
+
+	if _, err := runner.Run(ctx, &Command{Name: "pip", Args: []string{"freeze"}}); err == nil {
+		t.Error("Run() should fail for unscripted commands")
+	}
+
+	if len(runner.Calls()) != 4 {
+		t.Errorf("Calls() = %d, want 4", len(runner.Calls()))
+	}
+
+	if path, err := runner.LookPath("pip"); err != nil || path != "pip" {
+		t.Errorf("LookPath() = %q, %v", path, err)
+	}
+
+	runner.Paths = map[string]string{"python3": "/usr/bin/python3"}
+	if _, err := runner.LookPath("pip"); err == nil {
+		t.Error("LookPath() should fail for names missing from Paths")
+	}
+	if path, _ := runner.LookPath("python3"); path != "/usr/bin/python3" {
+		t.Errorf("LookPath(python3) = %q", path)
+	}
+}
+
+func TestManagerWithFakeRunner(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "--version").Respond("pip 23.3.1 from /site-packages/pip (python 3.11)\n", "", 0)
+	runner.On("python", "-m", "pip", "list", "--format=json").Respond(`[{"name": "requests", "version": "2.31.0"}]`, "", 0)
+	runner.On("python", "-m", "pip", "install", "missing").Respond("", "ERROR: No matching distribution found for missing\n", 1)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	if manager.GetCommandRunner() != runner {
+		t.Fatal("GetCommandRunner() should return the configured runner")
+	}
+
+	installed, err := manager.IsInstalled()
+	if err != nil || !installed {
+		t.Errorf("IsInstalled() = %v, %v", installed, err)
+	}
+
+	version, err := manager.GetVersion()
+	if err != nil || version != "23.3.1" {
+		t.Errorf("GetVersion() = %q, %v", version, err)
+	}
+
+	packages, err := manager.ListPackages()
+	if err != nil {
+		t.Fatalf("ListPackages() error: %v", err)
+	}
+	if len(packages) != 1 || packages[0].Name != "requests" {
+		t.Errorf("ListPackages() = %+v", packages)
+	}
+
+	err = manager.InstallPackage(&PackageSpec{Name: "missing"})
+	pipErr, ok := err.(*PipError)
+	if !ok {
+		t.Fatalf("InstallPackage() error = %T, want *PipError", err)
+	}
+	if pipErr.ExitCode != 1 || !strings.Contains(pipErr.Output, "No matching distribution") {
+		t.Errorf("unexpected error: %+v", pipErr)
+	}
+}
+
+func TestRecordReplayRunner(t *testing.T) {
+	tempDir := t.TempDir()
+	fixture := filepath.Join(tempDir, "fixtures", "session.json")
+
+	inner := NewFakeRunner()
+	inner.On("/usr/bin/pip", "--version").Respond("pip 23.0\n", "", 0)
+	inner.On("/usr/bin/pip", "show", "nothing").Respond("", "WARNING: Package(s) not found\n", 1)
+
+	recorder := NewRecordingRunner(inner, fixture)
+	ctx := context.Background()
+
+	if _, err := recorder.Run(ctx, &Command{Name: "/usr/bin/pip", Args: []string{"--version"}}); err != nil {
+		t.Fatalf("record run error: %v", err)
+	}
+	if _, err := recorder.Run(ctx, &Command{Name: "/usr/bin/pip", Args: []string{"show", "nothing"}}); err == nil {
+		t.Fatal("recorded failure should be returned")
+	}
+
+	replay, err := NewReplayRunner(fixture)
+	if err != nil {
+		t.Fatalf("NewReplayRunner() error: %v", err)
+	}
+
+	if path, err := replay.LookPath("pip"); err != nil || path != "/usr/bin/pip" {
+		t.Errorf("LookPath() = %q, %v", path, err)
+	}
+
+	// Replay matches on base name so fixtures are portable across machines
+	result, err := replay.Run(ctx, &Command{Name: "/opt/bin/pip", Args: []string{"--version"}})
+	if err != nil || result.Stdout != "pip 23.0\n" {
+		t.Errorf("replay Run() = %+v, %v", result, err)
+	}
+
+	result, err = replay.Run(ctx, &Command{Name: "pip", Args: []string{"show", "nothing"}})
+	if err == nil || result.ExitCode != 1 {
+		t.Errorf("replay Run() = %+v, %v; want recorded failure", result, err)
+	}
+
+	// Each recording is served only once
+	if _, err := replay.Run(ctx, &Command{Name: "pip", Args: []string{"--version"}}); err == nil {
+		t.Error("replay Run() should fail when recordings are exhausted")
+	}
+
+	if _, err := NewReplayRunner(filepath.Join(tempDir, "missing.json")); err == nil {
+		t.Error("NewReplayRunner() should fail for missing fixture")
+	}
+}
+
+func TestExecRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell-based test on Windows")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	runner := NewExecRunner()
+	ctx := context.Background()
+
+	result, err := runner.Run(ctx, &Command{Name: "sh", Args: []string{"-c", "echo out; echo err >&2; exit 3"}})
+	if err == nil {
+		t.Fatal("Run() should return an error for non-zero exit")
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3", result.ExitCode)
+	}
+	if result.Stdout != "out\n" || result.Stderr != "err\n" {
+		t.Errorf("Stdout = %q, Stderr = %q", result.Stdout, result.Stderr)
+	}
+	if !strings.Contains(result.Output, "out") || !strings.Contains(result.Output, "err") {
+		t.Errorf("Output = %q, want both streams", result.Output)
+	}
+
+	dir := t.TempDir()
+	result, err = runner.Run(ctx, &Command{Name: "sh", Args: []string{"-c", "pwd"}, Dir: dir})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	wantDir, _ := filepath.EvalSymlinks(dir)
+	gotDir, _ := filepath.EvalSymlinks(strings.TrimSpace(result.Stdout))
+	if gotDir != wantDir {
+		t.Errorf("Dir = %q, want %q", gotDir, wantDir)
+	}
+
+	result, err = runner.Run(ctx, &Command{Name: filepath.Join(os.TempDir(), "does-not-exist-pip")})
+	if err == nil || result.ExitCode != -1 {
+		t.Errorf("Run() = %+v, %v; want start failure", result, err)
+	}
+}
+
+func TestRunCommandAppliesProcessConfig(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("pip", "...").Respond("", "", 0)
+
+	limits := ResourceLimits{MaxMemoryBytes: 1 << 30, MaxOpenFiles: 128}
+	manager := NewManager(&Config{ResourceLimits: limits, KillGracePeriod: 2 * time.Second})
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.runCommand(&Command{Name: "pip", Args: []string{"list"}}); err != nil {
+		t.Fatalf("runCommand() error: %v", err)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	if calls[0].Limits != limits || calls[0].KillGracePeriod != 2*time.Second {
+		t.Errorf("command limits = %+v, grace = %v", calls[0].Limits, calls[0].KillGracePeriod)
+	}
+}
@@ -0,0 +1,151 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pipModuleSuffix turns an interpreter path into a pip invocation
+const pipModuleSuffix = " -m pip"
+
+// errScopedManager is returned when a scoped manager is asked to change its environment
+var errScopedManager = &PipError{
+	Type:    "scoped_manager",
+	Message: "scoped managers cannot activate or deactivate virtual environments",
+}
+
+// ForVenv returns a manager bound to the virtual environment at path.
+//
+// The returned manager shares the logger, command runner, progress reporter
+// and context of m but has its own configuration, so any number of scoped
+// managers can run operations concurrently without affecting each other or m.
+// ActivateVenv and DeactivateVenv are not supported on scoped managers.
+func (m *Manager) ForVenv(path string) (*Manager, error) {
+	if path == "" {
+		return nil, &PipError{
+			Type:    "invalid_path",
+			Message: "virtual environment path cannot be empty",
+		}
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, &PipError{
+			Type:    "invalid_path",
+			Message: fmt.Sprintf("invalid virtual environment path %s: %v", path, err),
+		}
+	}
+
+	if !m.isVenvValid(absPath) {
+		return nil, &PipError{
+			Type:    "venv_not_found",
+			Message: fmt.Sprintf("virtual environment not found or invalid: %s", path),
+		}
+	}
+
+	return m.clone(m.venvConfig(m.currentConfig(), absPath)), nil
+}
+
+// ForInterpreter returns a manager that runs pip as "<python> -m pip".
+// Like ForVenv, the returned manager is independent of m and of other
+// scoped managers.
+func (m *Manager) ForInterpreter(python string) (*Manager, error) {
+	if python == "" {
+		return nil, &PipError{
+			Type:    "invalid_path",
+			Message: "Python interpreter path cannot be empty",
+		}
+	}
+
+	if _, err := os.Stat(python); err != nil {
+		resolved, lookErr := m.lookPath(python)
+		if lookErr != nil {
+			return nil, &PipError{
+				Type:    "python_not_found",
+				Message: fmt.Sprintf("Python interpreter not found: %s", python),
+			}
+		}
+		python = resolved
+	}
+
+	config := copyConfig(m.currentConfig())
+	config.PythonPath = python
+	config.PipPath = python + pipModuleSuffix
+
+	return m.clone(config), nil
+}
+
+// venvConfig returns a copy of config pointing at the virtual environment at path
+func (m *Manager) venvConfig(config *Config, path string) *Config {
+	venvBinPath := m.getVenvBinPath(path)
+
+	result := copyConfig(config)
+	result.PythonPath = filepath.Join(venvBinPath, m.getPythonExecutableName())
+	result.PipPath = filepath.Join(venvBinPath, m.getPipExecutableName())
+
+	if result.Environment == nil {
+		result.Environment = make(map[string]string)
+	}
+	result.Environment["VIRTUAL_ENV"] = path
+	result.Environment["PATH"] = venvBinPath + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	return result
+}
+
+// clone returns a scoped manager with the given configuration that shares
+// everything else with m
+func (m *Manager) clone(config *Config) *Manager {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &Manager{
+		config:       config,
+		logger:       m.logger,
+		customLogger: m.customLogger,
+		runner:       m.runner,
+		progress:     m.progress,
+		journal:      m.journal,
+		interpreters: m.interpreters,
+		discovered:   m.discovered,
+		ctx:          m.ctx,
+		scoped:       true,
+	}
+}
+
+// copyConfig returns a deep copy of config
+func copyConfig(config *Config) *Config {
+	if config == nil {
+		return DefaultConfig()
+	}
+
+	result := *config
+	if config.TrustedHosts != nil {
+		result.TrustedHosts = append([]string{}, config.TrustedHosts...)
+	}
+	if config.WheelhouseDirs != nil {
+		result.WheelhouseDirs = append([]string{}, config.WheelhouseDirs...)
+	}
+	if config.EnvironmentAllowlist != nil {
+		result.EnvironmentAllowlist = append([]string{}, config.EnvironmentAllowlist...)
+	}
+	if config.ExtraOptions != nil {
+		result.ExtraOptions = make(map[string]string, len(config.ExtraOptions))
+		for k, v := range config.ExtraOptions {
+			result.ExtraOptions[k] = v
+		}
+	}
+	if config.Environment != nil {
+		result.Environment = make(map[string]string, len(config.Environment))
+		for k, v := range config.Environment {
+			result.Environment[k] = v
+		}
+	}
+	if config.IndexCredentials != nil {
+		result.IndexCredentials = make(map[string]IndexCredentials, len(config.IndexCredentials))
+		for k, v := range config.IndexCredentials {
+			result.IndexCredentials[k] = v
+		}
+	}
+	return &result
+}
@@ -0,0 +1,185 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// createFakeVenv creates the minimal layout recognized by isVenvValid
+func createFakeVenv(t *testing.T, m *Manager, path string) {
+	t.Helper()
+
+	binPath := m.getVenvBinPath(path)
+	if err := os.MkdirAll(binPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{m.getPythonExecutableName(), m.getPipExecutableName()} {
+		if err := os.WriteFile(filepath.Join(binPath, name), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestForVenv(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("pip", "list", "...").Respond(`[]`, "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	dir := t.TempDir()
+	venvA := filepath.Join(dir, "a")
+	venvB := filepath.Join(dir, "b")
+	createFakeVenv(t, manager, venvA)
+	createFakeVenv(t, manager, venvB)
+
+	scopedA, err := manager.ForVenv(venvA)
+	if err != nil {
+		t.Fatalf("ForVenv(a) error: %v", err)
+	}
+	scopedB, err := manager.ForVenv(venvB)
+	if err != nil {
+		t.Fatalf("ForVenv(b) error: %v", err)
+	}
+
+	if manager.GetConfig().PipPath != "" {
+		t.Errorf("ForVenv() modified the parent config: %q", manager.GetConfig().PipPath)
+	}
+	if scopedA.GetCommandRunner() != runner {
+		t.Error("scoped manager should share the command runner")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, scoped := range []*Manager{scopedA, scopedB} {
+			wg.Add(1)
+			go func(m *Manager) {
+				defer wg.Done()
+				if _, err := m.ListPackages(); err != nil {
+					t.Errorf("ListPackages() error: %v", err)
+				}
+			}(scoped)
+		}
+	}
+	wg.Wait()
+
+	counts := make(map[string]int)
+	for _, call := range runner.Calls() {
+		venv := filepath.Dir(filepath.Dir(call.Name))
+		counts[venv]++
+
+		if !containsString(call.Env, "VIRTUAL_ENV="+venv) {
+			t.Errorf("command %s ran without VIRTUAL_ENV=%s", call, venv)
+		}
+	}
+	if counts[venvA] != 10 || counts[venvB] != 10 {
+		t.Errorf("commands per venv = %v, want 10 each", counts)
+	}
+}
+
+func TestForVenvErrors(t *testing.T) {
+	manager := NewManager(nil)
+
+	if _, err := manager.ForVenv(""); err == nil {
+		t.Error("ForVenv(\"\") should fail")
+	}
+	if _, err := manager.ForVenv(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ForVenv() should fail for a missing venv")
+	}
+
+	dir := t.TempDir()
+	createFakeVenv(t, manager, dir)
+
+	scoped, err := manager.ForVenv(dir)
+	if err != nil {
+		t.Fatalf("ForVenv() error: %v", err)
+	}
+	if err := scoped.ActivateVenv(dir); err == nil {
+		t.Error("ActivateVenv() should fail on a scoped manager")
+	}
+	if err := scoped.DeactivateVenv(); err == nil {
+		t.Error("DeactivateVenv() should fail on a scoped manager")
+	}
+
+	pipPath := scoped.GetConfig().PipPath
+	scoped.SetConfig(&Config{})
+	if scoped.GetConfig().PipPath != pipPath {
+		t.Error("SetConfig() should be ignored on a scoped manager")
+	}
+}
+
+func TestForInterpreter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Python 3.11")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	python := filepath.Join(dir, "python")
+	if err := os.WriteFile(python, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "--version").Respond("pip 23.3.1 from /x (python 3.11)\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	scoped, err := manager.ForInterpreter(python)
+	if err != nil {
+		t.Fatalf("ForInterpreter() error: %v", err)
+	}
+
+	version, err := scoped.GetVersion()
+	if err != nil || version != "23.3.1" {
+		t.Errorf("GetVersion() = %q, %v", version, err)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 1 || calls[0].Name != python {
+		t.Errorf("command = %v, want interpreter %s", calls, python)
+	}
+
+	runner.Paths = map[string]string{}
+	if _, err := manager.ForInterpreter("python-missing"); err == nil {
+		t.Error("ForInterpreter() should fail for a missing interpreter")
+	}
+}
+
+func TestManagerConcurrentConfig(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("pip", "...").Respond("", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+	dir := t.TempDir()
+	createFakeVenv(t, manager, dir)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				manager.ActivateVenv(dir)
+			} else {
+				manager.DeactivateVenv()
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			manager.executePipCommandWithOutput("pip", []string{"list", fmt.Sprint(i)})
+			manager.SetProgressReporter(ProgressReporterFunc(func(*ProgressEvent) {}))
+		}(i)
+	}
+	wg.Wait()
+
+	for _, call := range runner.Calls() {
+		if !strings.HasPrefix(call.String(), "pip list") {
+			t.Errorf("unexpected command %s", call)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package pip
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultIndexURL is used when Config.DefaultIndex is not set
+	defaultIndexURL = "https://pypi.org/simple/"
+
+	// defaultSearchLimit is the number of results returned when SearchOptions.Limit is zero
+	defaultSearchLimit = 20
+
+	// searchWorkers bounds concurrent metadata requests
+	searchWorkers = 8
+
+	// Content types of the simple repository API (PEP 691)
+	simpleJSONContentType = "application/vnd.pypi.simple.v1+json"
+	simpleHTMLContentType = "application/vnd.pypi.simple.v1+html"
+	simpleAcceptHeader    = simpleJSONContentType + ", " + simpleHTMLContentType + ";q=0.2, text/html;q=0.01"
+)
+
+// SearchOptions controls SearchPackagesWithOptions
+type SearchOptions struct {
+	// Index is the simple repository URL to search, e.g.
+	// "https://nexus.example.com/repository/pypi/simple/". Defaults to
+	// Config.DefaultIndex, then PyPI.
+	Index string `json:"index,omitempty"`
+
+	// Limit is the maximum number of results; zero returns 20
+	Limit int `json:"limit,omitempty"`
+}
+
+var (
+	anchorRegex    = regexp.MustCompile(`(?is)<a\s([^>]*)>(.*?)</a>`)
+	attributeRegex = regexp.MustCompile(`(?s)([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// SearchPackages searches the package index for projects whose names match
+// query. See SearchPackagesWithOptions.
+func (m *Manager) SearchPackages(query string) ([]*SearchResult, error) {
+	return m.SearchPackagesWithOptions(query, nil)
+}
+
+// SearchPackagesWithOptions searches a simple repository index for projects
+// whose names match query, exactly, by prefix, by substring or by spelling
+// similarity. Results are ordered by Score, from 1 for an exact match down,
+// and include the latest version and summary of each project.
+//
+// Project names are read from the index root using the PEP 691 JSON API,
+// falling back to PEP 503 HTML. Versions and summaries come from the
+// project's JSON metadata endpoint ("<base>/pypi/<name>/json", as served by
+// PyPI and most mirrors), or from the files on the project's simple page
+// when that endpoint is unavailable.
+func (m *Manager) SearchPackagesWithOptions(query string, opts *SearchOptions) ([]*SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, &PipError{
+			Type:    "invalid_package_spec",
+			Message: "search query cannot be empty",
+		}
+	}
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	client := m.IndexClient(opts.Index)
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	m.logDebug("Searching packages: %s in %s", query, redactValue(client.URL()))
+
+	projects, err := client.Projects()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*SearchResult
+	for _, name := range projects {
+		if score := searchScore(query, name); score > 0 {
+			results = append(results, &SearchResult{Name: name, Score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return normalizePackageName(results[i].Name) < normalizePackageName(results[j].Name)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	// Fill in versions and summaries concurrently
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, searchWorkers)
+	for _, result := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *SearchResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m.fillSearchResult(client, result)
+		}(result)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// fillSearchResult sets the version and summary of a search result,
+// logging rather than failing when the index has no metadata for it
+func (m *Manager) fillSearchResult(client *IndexClient, result *SearchResult) {
+	if info, err := client.projectInfo(result.Name); err == nil {
+		result.Version = info.Version
+		result.Summary = info.Summary
+		if info.Name != "" {
+			result.Name = info.Name
+		}
+		return
+	}
+
+	project, err := client.Project(result.Name)
+	if err != nil {
+		m.logDebug("No metadata for %s: %v", result.Name, err)
+		return
+	}
+	if latest := project.Latest(); latest != nil {
+		result.Version = latest.Version
+	}
+}
+
+// searchScore rates how well a project name matches a query, from 0 for
+// no match to 1 for an exact match. Names are compared as described in
+// PEP 503, so "Zope_Interface" matches "zope.interface" exactly.
+func searchScore(query, name string) float64 {
+	q, n := normalizePackageName(strings.TrimSpace(query)), normalizePackageName(name)
+	if q == "" || n == "" {
+		return 0
+	}
+
+	// How much of the name the query covers
+	coverage := float64(len(q)) / float64(len(n))
+
+	switch {
+	case q == n:
+		return 1
+	case strings.HasPrefix(n, q):
+		return 0.7 + 0.2*coverage
+	case strings.Contains(n, q):
+		return 0.5 + 0.2*coverage
+	}
+
+	// Spelling similarity, for typos such as "reqests"
+	longest := len(q)
+	if len(n) > longest {
+		longest = len(n)
+	}
+	if diff := len(q) - len(n); diff*10 > longest*3 || -diff*10 > longest*3 {
+		return 0
+	}
+	similarity := 1 - float64(levenshtein(q, n))/float64(longest)
+	if similarity < 0.7 {
+		return 0
+	}
+	return 0.5 * similarity
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// projectInfo is the part of the JSON metadata endpoint search needs
+type projectInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Summary string `json:"summary"`
+}
+
+// projectJSONURL returns the JSON metadata endpoint for a project, replacing
+// a trailing "simple/" in the index URL with "pypi/<name>/json"
+func projectJSONURL(index, name string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(index, "/"), "/simple")
+	return base + "/pypi/" + url.PathEscape(normalizePackageName(name)) + "/json"
+}
+
+// anchor is a link parsed from a PEP 503 HTML page
+type anchor struct {
+	attrs map[string]string
+	text  string
+}
+
+// parseAnchors extracts the links from a simple repository HTML page
+func parseAnchors(page string) []*anchor {
+	var anchors []*anchor
+	for _, match := range anchorRegex.FindAllStringSubmatch(page, -1) {
+		a := &anchor{
+			attrs: make(map[string]string),
+			text:  html.UnescapeString(match[2]),
+		}
+		for _, attr := range attributeRegex.FindAllStringSubmatch(match[1], -1) {
+			a.attrs[strings.ToLower(attr[1])] = html.UnescapeString(attr[2] + attr[3] + attr[4])
+		}
+		// Valueless attributes such as a bare data-yanked
+		for _, field := range strings.Fields(attributeRegex.ReplaceAllString(match[1], "")) {
+			if _, ok := a.attrs[strings.ToLower(field)]; !ok {
+				a.attrs[strings.ToLower(field)] = ""
+			}
+		}
+		anchors = append(anchors, a)
+	}
+	return anchors
+}
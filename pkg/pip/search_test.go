@@ -0,0 +1,212 @@
+package pip
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestIndex serves a simple repository with the given projects. With
+// jsonAPI it answers PEP 691 JSON requests, otherwise only PEP 503 HTML.
+// The JSON metadata endpoint is served for projects in summaries.
+func newTestIndex(t *testing.T, jsonAPI bool, projects map[string][]string, summaries map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/simple/", func(w http.ResponseWriter, r *http.Request) {
+		wantJSON := jsonAPI && strings.Contains(r.Header.Get("Accept"), simpleJSONContentType)
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/simple/"), "/")
+
+		if name == "" {
+			var names []string
+			for project := range projects {
+				names = append(names, project)
+			}
+			if wantJSON {
+				w.Header().Set("Content-Type", simpleJSONContentType)
+				var entries []string
+				for _, project := range names {
+					entries = append(entries, fmt.Sprintf(`{"name": %q}`, project))
+				}
+				fmt.Fprintf(w, `{"meta": {"api-version": "1.0"}, "projects": [%s]}`, strings.Join(entries, ", "))
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<!DOCTYPE html><html><body>\n")
+			for _, project := range names {
+				fmt.Fprintf(w, "<a href=\"/simple/%s/\">%s</a><br/>\n", normalizePackageName(project), project)
+			}
+			fmt.Fprint(w, "</body></html>")
+			return
+		}
+
+		for project, files := range projects {
+			if normalizePackageName(project) != name {
+				continue
+			}
+			w.Header().Set("Content-Type", "text/html")
+			for _, file := range files {
+				yanked := ""
+				if strings.Contains(file, "yanked") {
+					yanked = " data-yanked=\"\""
+					file = strings.Replace(file, "yanked-", "", 1)
+				}
+				fmt.Fprintf(w, "<a href=\"../../files/%s#sha256=00\"%s>%s</a>\n", file, yanked, file)
+			}
+			return
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/pypi/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/pypi/"), "/json")
+		for project, summary := range summaries {
+			if normalizePackageName(project) == name {
+				w.Header().Set("Content-Type", "application/json")
+				version := ""
+				if files := projects[project]; len(files) > 0 {
+					_, version = splitDistFilename(files[len(files)-1])
+				}
+				fmt.Fprintf(w, `{"info": {"name": %q, "version": %q, "summary": %q}}`, project, version, summary)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+var testIndexProjects = map[string][]string{
+	"requests":          {"requests-2.30.0.tar.gz", "requests-2.31.0-py3-none-any.whl"},
+	"requests-oauthlib": {"requests_oauthlib-1.3.1-py2.py3-none-any.whl"},
+	"types-requests":    {"types_requests-2.31.0.10-py3-none-any.whl"},
+	"reqeusts":          {"reqeusts-0.1.tar.gz"},
+	"Flask":             {"Flask-3.0.0-py3-none-any.whl", "flask-3.1.0rc1.tar.gz", "yanked-flask-3.0.1.tar.gz"},
+	"numpy":             {"numpy-1.26.0.tar.gz"},
+}
+
+func TestSearchPackages(t *testing.T) {
+	for _, jsonAPI := range []bool{true, false} {
+		t.Run(fmt.Sprintf("json=%v", jsonAPI), func(t *testing.T) {
+			server := newTestIndex(t, jsonAPI, testIndexProjects, map[string]string{
+				"requests": "Python HTTP for Humans.",
+			})
+
+			manager := NewManager(&Config{DefaultIndex: server.URL + "/simple"})
+			results, err := manager.SearchPackages("Requests")
+			if err != nil {
+				t.Fatalf("SearchPackages() error: %v", err)
+			}
+
+			var names []string
+			for _, result := range results {
+				names = append(names, result.Name)
+			}
+			want := []string{"requests", "requests-oauthlib", "types-requests", "reqeusts"}
+			if strings.Join(names, ",") != strings.Join(want, ",") {
+				t.Fatalf("results = %v, want %v", names, want)
+			}
+
+			if results[0].Score != 1 || results[0].Version != "2.31.0" || results[0].Summary != "Python HTTP for Humans." {
+				t.Errorf("exact match = %+v", results[0])
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score <= 0 || results[i].Score >= results[i-1].Score {
+					t.Errorf("scores not descending: %s %v after %v", results[i].Name, results[i].Score, results[i-1].Score)
+				}
+			}
+
+			// Versions fall back to the simple page without a JSON endpoint
+			if results[1].Version != "1.3.1" || results[1].Summary != "" {
+				t.Errorf("fallback result = %+v", results[1])
+			}
+		})
+	}
+}
+
+func TestSearchPackagesOptions(t *testing.T) {
+	server := newTestIndex(t, true, testIndexProjects, nil)
+	manager := NewManager(&Config{DefaultIndex: "http://127.0.0.1:1/unused/"})
+
+	results, err := manager.SearchPackagesWithOptions("flask", &SearchOptions{Index: server.URL + "/simple/", Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchPackagesWithOptions() error: %v", err)
+	}
+	// Yanked files and pre-releases are not the latest version
+	if len(results) != 1 || results[0].Name != "Flask" || results[0].Version != "3.0.0" {
+		t.Errorf("results = %+v", results)
+	}
+
+	if _, err := manager.SearchPackages("flask"); !IsErrorType(err, ErrorTypeNetworkError) {
+		t.Errorf("unreachable index error = %v, want %s", err, ErrorTypeNetworkError)
+	}
+	if _, err := manager.SearchPackages(" "); err == nil {
+		t.Error("SearchPackages() with empty query should return error")
+	}
+}
+
+func TestSearchPackagesCredentials(t *testing.T) {
+	server := newTestIndex(t, true, testIndexProjects, nil)
+	protected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "ci" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer protected.Close()
+
+	index := strings.Replace(protected.URL, "http://", "http://ci:secret@", 1) + "/simple/"
+	results, err := NewManager(&Config{DefaultIndex: index}).SearchPackages("numpy")
+	if err != nil || len(results) == 0 || results[0].Name != "numpy" {
+		t.Fatalf("SearchPackages() = %v, %v", results, err)
+	}
+
+	_, err = NewManager(&Config{DefaultIndex: protected.URL + "/simple/"}).SearchPackages("numpy")
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("unauthenticated search error = %v", err)
+	}
+}
+
+func TestSearchScore(t *testing.T) {
+	tests := []struct {
+		query, name string
+		min, max    float64
+	}{
+		{"Zope_Interface", "zope.interface", 1, 1},
+		{"requests", "requests-oauthlib", 0.7, 0.9},
+		{"requests", "types-requests", 0.5, 0.7},
+		{"reqeusts", "requests", 0.35, 0.5},
+		{"requests", "numpy", 0, 0},
+		{"a", "abcdefghijklmnop", 0.7, 0.72},
+	}
+
+	for _, tt := range tests {
+		if got := searchScore(tt.query, tt.name); got < tt.min || got > tt.max {
+			t.Errorf("searchScore(%q, %q) = %v, want in [%v, %v]", tt.query, tt.name, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestParseAnchors(t *testing.T) {
+	page := `<a href="https://files.example/a-1.0.tar.gz#sha256=ab" data-requires-python="&gt;=3.8" data-yanked>a-1.0.tar.gz</a>
+<A HREF='b-2.0.whl'>b-2.0.whl</A>`
+
+	anchors := parseAnchors(page)
+	if len(anchors) != 2 {
+		t.Fatalf("got %d anchors, want 2", len(anchors))
+	}
+	if anchors[0].attrs["data-requires-python"] != ">=3.8" || anchors[0].text != "a-1.0.tar.gz" {
+		t.Errorf("anchor = %+v", anchors[0])
+	}
+	if _, ok := anchors[0].attrs["data-yanked"]; !ok {
+		t.Error("valueless data-yanked attribute not parsed")
+	}
+	if anchors[1].attrs["href"] != "b-2.0.whl" {
+		t.Errorf("anchor = %+v", anchors[1])
+	}
+}
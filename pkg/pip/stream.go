@@ -0,0 +1,183 @@
+package pip
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream identifies which output stream a line came from
+type Stream string
+
+const (
+	StreamStdout Stream = "stdout"
+	StreamStderr Stream = "stderr"
+)
+
+// OutputLine is a single line of subprocess output
+type OutputLine struct {
+	Stream Stream    `json:"stream"`
+	Text   string    `json:"text"`
+	Time   time.Time `json:"time"`
+}
+
+// OutputHandler receives output lines while a command runs.
+// Calls are serialized, so handlers do not need their own locking.
+type OutputHandler func(line OutputLine)
+
+// ChannelHandler returns an OutputHandler that sends every line to ch.
+// The caller owns the channel and should close it after the operation returns.
+func ChannelHandler(ch chan<- OutputLine) OutputHandler {
+	return func(line OutputLine) {
+		ch <- line
+	}
+}
+
+// lineWriter splits written bytes into lines and forwards them to a handler
+type lineWriter struct {
+	stream  Stream
+	handler OutputHandler
+	mu      *sync.Mutex // shared between the stdout and stderr writers
+	buf     bytes.Buffer
+}
+
+// newLineWriter creates a line writer for the given stream
+func newLineWriter(stream Stream, handler OutputHandler, mu *sync.Mutex) *lineWriter {
+	return &lineWriter{
+		stream:  stream,
+		handler: handler,
+		mu:      mu,
+	}
+}
+
+// Write buffers p and emits every complete line
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(w.buf.Next(idx + 1))
+		w.emit(line)
+	}
+
+	return len(p), nil
+}
+
+// Flush emits any trailing partial line
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+// emit sends a single line to the handler with line endings removed
+func (w *lineWriter) emit(line string) {
+	w.handler(OutputLine{
+		Stream: w.stream,
+		Text:   strings.TrimRight(line, "\r\n"),
+		Time:   time.Now(),
+	})
+}
+
+// InstallPackageStream installs a package, streaming pip output to handler.
+// The returned InstallResult is always populated; its Error matches the
+// returned error.
+func (m *Manager) InstallPackageStream(pkg *PackageSpec, handler OutputHandler) (*InstallResult, error) {
+	if err := m.validatePackageSpec(pkg); err != nil {
+		return &InstallResult{Package: pkg, Error: err, Message: err.Error()}, err
+	}
+	if err := m.checkWheelhouse([]*PackageSpec{pkg}, nil); err != nil {
+		return &InstallResult{Package: pkg, Error: err, Message: err.Error()}, err
+	}
+
+	m.logInfo("Installing package: %s", pkg.Name)
+
+	return m.runInstallStream(pkg, m.buildInstallArgs(pkg), handler)
+}
+
+// InstallRequirementsStream installs packages from a requirements file,
+// streaming pip output to handler
+func (m *Manager) InstallRequirementsStream(path string, handler OutputHandler) (*InstallResult, error) {
+	if err := m.validateRequirementsFile(path); err != nil {
+		return &InstallResult{Error: err, Message: err.Error()}, err
+	}
+	if err := m.checkWheelhouse(nil, []string{path}); err != nil {
+		return &InstallResult{Error: err, Message: err.Error()}, err
+	}
+
+	m.logInfo("Installing requirements from: %s", path)
+
+	return m.runInstallStream(nil, []string{"install", "-r", path}, handler)
+}
+
+// runInstallStream runs a pip install command and builds its InstallResult
+func (m *Manager) runInstallStream(pkg *PackageSpec, args []string, handler OutputHandler) (*InstallResult, error) {
+	result := &InstallResult{Package: pkg}
+	start := time.Now()
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		result.Error = ErrPipNotInstalled
+		result.Message = ErrPipNotInstalled.Error()
+		return result, result.Error
+	}
+
+	if m.planPipCommand(pipPath, args, "install packages") {
+		result.Success = true
+		result.Message = "dry run: install planned"
+		return result, nil
+	}
+
+	progress := m.progressHandler()
+	collect := func(line OutputLine) {
+		result.OutputLines = append(result.OutputLines, line.Text)
+		if progress != nil {
+			progress(line)
+		}
+		if handler != nil {
+			handler(line)
+		}
+	}
+
+	_, err = m.executePipCommandStreaming(pipPath, args, collect, func() { result.OutputLines = nil })
+	result.Duration = time.Since(start)
+
+	if err != nil {
+		result.Error = err
+		result.Message = err.Error()
+		return result, err
+	}
+
+	result.Success = true
+	result.Message = installSummary(result.OutputLines, pkg)
+	return result, nil
+}
+
+// installSummary picks the most informative line from pip install output
+func installSummary(lines []string, pkg *PackageSpec) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "Successfully installed") {
+			return lines[i]
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "Requirement already satisfied") {
+			return line
+		}
+	}
+	if pkg != nil {
+		return fmt.Sprintf("Package %s installed successfully", pkg.Name)
+	}
+	return "Requirements installed successfully"
+}
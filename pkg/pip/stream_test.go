@@ -0,0 +1,137 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []OutputLine
+	var mu sync.Mutex
+	w := newLineWriter(StreamStderr, func(line OutputLine) {
+		lines = append(lines, line)
+	}, &mu)
+
+	w.Write([]byte("Collecting req"))
+	w.Write([]byte("uests\r\nDownloading"))
+	w.Write([]byte(" requests.whl\n\nlast"))
+
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines before flush, want 3", len(lines))
+	}
+
+	w.Flush()
+
+	var texts []string
+	for _, line := range lines {
+		if line.Stream != StreamStderr {
+			t.Errorf("Stream = %s, want %s", line.Stream, StreamStderr)
+		}
+		texts = append(texts, line.Text)
+	}
+
+	want := []string{"Collecting requests", "Downloading requests.whl", "", "last"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("lines = %q, want %q", texts, want)
+	}
+}
+
+func TestInstallPackageStream(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "requests", "--upgrade").Respond(
+		"Collecting requests\nInstalling collected packages: requests\nSuccessfully installed requests-2.31.0\n",
+		"WARNING: something\n", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	var streamed []OutputLine
+	result, err := manager.InstallPackageStream(&PackageSpec{Name: "requests", Upgrade: true}, func(line OutputLine) {
+		streamed = append(streamed, line)
+	})
+	if err != nil {
+		t.Fatalf("InstallPackageStream() error: %v", err)
+	}
+
+	if !result.Success {
+		t.Error("Success should be true")
+	}
+	if result.Message != "Successfully installed requests-2.31.0" {
+		t.Errorf("Message = %q", result.Message)
+	}
+	if result.Package == nil || result.Package.Name != "requests" {
+		t.Errorf("Package = %+v", result.Package)
+	}
+	if len(result.OutputLines) != 4 || len(streamed) != 4 {
+		t.Errorf("OutputLines = %d, streamed = %d, want 4", len(result.OutputLines), len(streamed))
+	}
+
+	var stderrLines int
+	for _, line := range streamed {
+		if line.Stream == StreamStderr {
+			stderrLines++
+		}
+	}
+	if stderrLines != 1 {
+		t.Errorf("stderr lines = %d, want 1", stderrLines)
+	}
+}
+
+func TestInstallPackageStreamFailure(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "missing").Respond("", "ERROR: No matching distribution found for missing\n", 1)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	result, err := manager.InstallPackageStream(&PackageSpec{Name: "missing"}, nil)
+	if err == nil {
+		t.Fatal("InstallPackageStream() should fail")
+	}
+	if result.Success || result.Error != err {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.OutputLines) != 1 || !strings.Contains(result.OutputLines[0], "No matching distribution") {
+		t.Errorf("OutputLines = %q", result.OutputLines)
+	}
+
+	result, err = manager.InstallPackageStream(nil, nil)
+	if err == nil || result == nil || result.Error == nil {
+		t.Error("InstallPackageStream(nil) should return a populated failed result")
+	}
+}
+
+func TestInstallRequirementsStream(t *testing.T) {
+	reqFile := filepath.Join(t.TempDir(), "requirements.txt")
+	if err := os.WriteFile(reqFile, []byte("requests\n"), 0644); err != nil {
+		t.Fatalf("Failed to write requirements: %v", err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "install", "-r", reqFile).Respond("Requirement already satisfied: requests in /site-packages\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	ch := make(chan OutputLine, 10)
+	result, err := manager.InstallRequirementsStream(reqFile, ChannelHandler(ch))
+	close(ch)
+	if err != nil {
+		t.Fatalf("InstallRequirementsStream() error: %v", err)
+	}
+
+	if len(ch) != 1 {
+		t.Errorf("channel received %d lines, want 1", len(ch))
+	}
+	if !strings.HasPrefix(result.Message, "Requirement already satisfied") {
+		t.Errorf("Message = %q", result.Message)
+	}
+
+	if _, err := manager.InstallRequirementsStream(filepath.Join(t.TempDir(), "missing.txt"), nil); err == nil {
+		t.Error("InstallRequirementsStream() should fail for missing file")
+	}
+}
@@ -60,6 +60,25 @@ type Package struct {
 	Location  string `json:"location,omitempty"`
 	Editable  bool   `json:"editable,omitempty"`
 	Installer string `json:"installer,omitempty"`
+
+	// EditableLocation is the project directory of an editable install
+	EditableLocation string `json:"editable_project_location,omitempty"`
+
+	// Set by ListOutdated
+	LatestVersion  string `json:"latest_version,omitempty"`
+	LatestFiletype string `json:"latest_filetype,omitempty"` // "wheel" or "sdist"
+}
+
+// ListOptions filters the packages returned by ListPackagesWithOptions
+type ListOptions struct {
+	NotRequired     bool     `json:"not_required,omitempty"`     // only packages no other package depends on
+	User            bool     `json:"user,omitempty"`             // only packages in the user site
+	Local           bool     `json:"local,omitempty"`            // skip globally installed packages inside a virtualenv
+	Editable        bool     `json:"editable,omitempty"`         // only editable installs
+	ExcludeEditable bool     `json:"exclude_editable,omitempty"` // skip editable installs
+	Exclude         []string `json:"exclude,omitempty"`          // package names to leave out
+	Path            []string `json:"path,omitempty"`             // only packages installed in these directories
+	Verbose         bool     `json:"verbose,omitempty"`          // populate Location and Installer
 }
 
 // PackageInfo represents detailed package information
@@ -110,22 +129,45 @@ type VenvInfo struct {
 	IsActive      bool      `json:"is_active"`
 	CreatedAt     time.Time `json:"created_at,omitempty"`
 	PythonVersion string    `json:"python_version,omitempty"`
+	SitePackages  string    `json:"site_packages,omitempty"`
+	ScriptsPath   string    `json:"scripts_path,omitempty"`
 }
 
 // InstallResult represents the result of a package installation
 type InstallResult struct {
 	Package     *PackageSpec  `json:"package"`
 	Success     bool          `json:"success"`
+	Version     string        `json:"version,omitempty"` // installed version, when reported by pip
 	Message     string        `json:"message,omitempty"`
 	Error       error         `json:"error,omitempty"`
 	Duration    time.Duration `json:"duration"`
 	OutputLines []string      `json:"output_lines,omitempty"`
 }
 
+// InstallOptions controls a batch install. Settings apply to the whole pip
+// run and take precedence over the corresponding PackageSpec fields.
+type InstallOptions struct {
+	Upgrade        bool              `json:"upgrade,omitempty"`
+	ForceReinstall bool              `json:"force_reinstall,omitempty"`
+	NoDeps         bool              `json:"no_deps,omitempty"`
+	Index          string            `json:"index,omitempty"`   // custom index URL
+	Options        map[string]string `json:"options,omitempty"` // additional pip options
+	Handler        OutputHandler     `json:"-"`                 // receives output lines as they are produced
+}
+
+// UninstallOptions controls UninstallPackages
+type UninstallOptions struct {
+	Requirements []string      `json:"requirements,omitempty"` // requirement files whose packages are removed (-r)
+	Autoremove   bool          `json:"autoremove,omitempty"`   // also remove dependencies nothing else requires
+	Keep         []string      `json:"keep,omitempty"`         // never autoremoved, in addition to pip, setuptools and wheel
+	Handler      OutputHandler `json:"-"`                      // receives output lines as they are produced
+}
+
 // UninstallResult represents the result of a package uninstallation
 type UninstallResult struct {
 	PackageName string        `json:"package_name"`
 	Success     bool          `json:"success"`
+	Autoremoved bool          `json:"autoremoved,omitempty"` // removed as an unused dependency
 	Message     string        `json:"message,omitempty"`
 	Error       error         `json:"error,omitempty"`
 	Duration    time.Duration `json:"duration"`
@@ -138,12 +180,51 @@ type Config struct {
 	PipPath      string            `json:"pip_path,omitempty"`
 	DefaultIndex string            `json:"default_index,omitempty"`
 	TrustedHosts []string          `json:"trusted_hosts,omitempty"`
-	Timeout      time.Duration     `json:"timeout,omitempty"`
-	Retries      int               `json:"retries,omitempty"`
+	Timeout      time.Duration     `json:"timeout,omitempty"`     // per-command timeout, zero disables
+	Retries      int               `json:"retries,omitempty"`     // retries for transient failures
+	RetryDelay   time.Duration     `json:"retry_delay,omitempty"` // base delay for exponential backoff
 	LogLevel     string            `json:"log_level,omitempty"`
 	CacheDir     string            `json:"cache_dir,omitempty"`
 	ExtraOptions map[string]string `json:"extra_options,omitempty"`
-	Environment  map[string]string `json:"environment,omitempty"`
+	Environment  map[string]string `json:"environment,omitempty"` // overrides layered on top of the policy
+
+	// EnvironmentPolicy selects which parent variables subprocesses inherit.
+	// PIP_* and PYTHON* parent variables are always removed unless allowlisted.
+	EnvironmentPolicy    EnvironmentPolicy `json:"environment_policy,omitempty"`
+	EnvironmentAllowlist []string          `json:"environment_allowlist,omitempty"` // names or globs, e.g. "LC_*"
+
+	// KillGracePeriod is how long a cancelled subprocess group has to exit
+	// after SIGTERM before it is killed. Zero uses the 5 second default.
+	KillGracePeriod time.Duration  `json:"kill_grace_period,omitempty"`
+	ResourceLimits  ResourceLimits `json:"resource_limits,omitempty"`
+
+	// DryRun makes operations that change the system record their commands,
+	// file writes and removals in Manager.Plan instead of performing them
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// PythonVersion selects the newest discovered interpreter matching this
+	// PEP 440 constraint, e.g. ">=3.10,<3.13", when PythonPath is not set
+	PythonVersion string `json:"python_version,omitempty"`
+
+	// IndexCredentials are sent as basic auth by IndexClient and
+	// SearchPackages, keyed by index host name, when the index URL has no
+	// credentials of its own
+	IndexCredentials map[string]IndexCredentials `json:"index_credentials,omitempty"`
+
+	// Offline makes pip commands use only WheelhouseDirs (--no-index
+	// --find-links) and installs fail early with a WheelhouseError when a
+	// requested requirement has no matching file there
+	Offline        bool     `json:"offline,omitempty"`
+	WheelhouseDirs []string `json:"wheelhouse_dirs,omitempty"`
+}
+
+// ResourceLimits are per-process limits applied to pip subprocesses and
+// everything they spawn. Zero values leave the inherited limit unchanged.
+// Limits are not supported on Windows and are ignored there.
+type ResourceLimits struct {
+	MaxMemoryBytes uint64 `json:"max_memory_bytes,omitempty"` // address space (ulimit -v)
+	MaxCPUSeconds  uint64 `json:"max_cpu_seconds,omitempty"`  // CPU time (ulimit -t)
+	MaxOpenFiles   uint64 `json:"max_open_files,omitempty"`   // file descriptors (ulimit -n)
 }
 
 // Error types
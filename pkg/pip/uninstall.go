@@ -0,0 +1,347 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// protectedPackages are never removed by autoremove
+var protectedPackages = []string{"pip", "setuptools", "wheel"}
+
+// UninstallPackages removes packages given by name and in requirement files
+// with a single pip run. With opts.Autoremove it also removes dependencies
+// of those packages that no remaining package requires and that were not
+// themselves installed by name. It returns one
+// result per removed or requested package; autoremoved packages are
+// marked as such.
+func (m *Manager) UninstallPackages(names []string, opts *UninstallOptions) ([]*UninstallResult, error) {
+	if opts == nil {
+		opts = &UninstallOptions{}
+	}
+
+	if len(names) == 0 && len(opts.Requirements) == 0 {
+		return nil, &PipError{
+			Type:    "invalid_package_spec",
+			Message: "no packages to uninstall",
+		}
+	}
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return nil, &PipError{
+				Type:    "invalid_package_spec",
+				Message: "package name cannot be empty",
+			}
+		}
+	}
+
+	// Collect the requested packages, in order and without duplicates
+	requested := append([]string{}, names...)
+	for _, path := range opts.Requirements {
+		if err := m.validateRequirementsFile(path); err != nil {
+			return nil, err
+		}
+		fileNames, err := requirementFileNames(path)
+		if err != nil {
+			return nil, &PipError{
+				Type:    "file_not_found",
+				Message: fmt.Sprintf("failed to read requirements file: %v", err),
+			}
+		}
+		requested = append(requested, fileNames...)
+	}
+
+	var results []*UninstallResult
+	seen := make(map[string]bool)
+	for _, name := range requested {
+		if key := normalizePackageName(name); !seen[key] {
+			seen[key] = true
+			results = append(results, &UninstallResult{PackageName: name})
+		}
+	}
+
+	m.logInfo("Uninstalling %d packages", len(results))
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return results, ErrPipNotInstalled
+	}
+
+	if opts.Autoremove {
+		targets := make([]string, 0, len(results))
+		for _, result := range results {
+			targets = append(targets, result.PackageName)
+		}
+
+		orphans, err := m.autoremoveCandidates(pipPath, targets, opts.Keep)
+		if err != nil {
+			return results, err
+		}
+		for _, name := range orphans {
+			results = append(results, &UninstallResult{PackageName: name, Autoremoved: true})
+		}
+	}
+
+	args := []string{"uninstall", "-y"}
+	args = append(args, names...)
+	for _, result := range results {
+		if result.Autoremoved {
+			args = append(args, result.PackageName)
+		}
+	}
+	for _, path := range opts.Requirements {
+		args = append(args, "-r", path)
+	}
+
+	if m.planPipCommand(pipPath, args, "uninstall packages") {
+		for _, result := range results {
+			result.Success = true
+			result.Message = "dry run: uninstall planned"
+		}
+		return results, nil
+	}
+
+	var lines []string
+	collect := func(line OutputLine) {
+		lines = append(lines, line.Text)
+		if opts.Handler != nil {
+			opts.Handler(line)
+		}
+	}
+
+	start := time.Now()
+	_, err = m.executePipCommandStreaming(pipPath, args, collect, func() { lines = nil })
+	attributeUninstallResults(results, lines, time.Since(start), err)
+	return results, err
+}
+
+// attributeUninstallResults fills per-package results from pip uninstall output
+func attributeUninstallResults(results []*UninstallResult, lines []string, duration time.Duration, err error) {
+	uninstalled := make(map[string]string)
+	skipped := make(map[string]string)
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Successfully uninstalled "):
+			name, _ := splitNameVersion(strings.TrimPrefix(trimmed, "Successfully uninstalled "))
+			uninstalled[normalizePackageName(name)] = trimmed
+		case strings.HasPrefix(trimmed, "WARNING: Skipping ") && strings.HasSuffix(trimmed, " as it is not installed."):
+			name := strings.TrimSuffix(strings.TrimPrefix(trimmed, "WARNING: Skipping "), " as it is not installed.")
+			skipped[normalizePackageName(name)] = trimmed
+		}
+	}
+
+	for _, result := range results {
+		result.Duration = duration
+		result.OutputLines = lines
+		key := normalizePackageName(result.PackageName)
+
+		switch {
+		case uninstalled[key] != "":
+			result.Success = true
+			result.Message = uninstalled[key]
+		case err != nil:
+			result.Error = err
+			result.Message = err.Error()
+		case skipped[key] != "":
+			result.Error = &PipError{
+				Type:    string(ErrorTypePackageNotFound),
+				Message: fmt.Sprintf("package %s is not installed", result.PackageName),
+			}
+			result.Message = skipped[key]
+		default:
+			result.Success = true
+			result.Message = fmt.Sprintf("Package %s uninstalled", result.PackageName)
+		}
+	}
+}
+
+// autoremoveCandidates returns installed dependencies of targets that are
+// not required by any package that stays installed, nor requested by the
+// user
+func (m *Manager) autoremoveCandidates(pipPath string, targets []string, keep []string) ([]string, error) {
+	installed, err := m.ListPackages()
+	if err != nil {
+		return nil, err
+	}
+	if len(installed) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"show"}
+	for _, pkg := range installed {
+		args = append(args, pkg.Name)
+	}
+	output, err := m.executePipCommandWithOutput(pipPath, args)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := make(dependencyGraph)
+	for _, info := range m.parseShowOutputs(output) {
+		graph[normalizePackageName(info.Name)] = info
+	}
+
+	keep = append(append(append([]string{}, protectedPackages...), keep...), graph.requested()...)
+	return graph.orphans(targets, keep), nil
+}
+
+// parseShowOutputs parses "pip show" output for several packages, which pip
+// separates with "---" lines
+func (m *Manager) parseShowOutputs(output string) []*PackageInfo {
+	var infos []*PackageInfo
+	var section []string
+
+	flush := func() {
+		if len(section) > 0 {
+			if info := m.parseShowOutput(strings.Join(section, "\n")); info.Name != "" {
+				infos = append(infos, info)
+			}
+			section = nil
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "---" {
+			flush()
+			continue
+		}
+		section = append(section, strings.TrimRight(line, "\r"))
+	}
+	flush()
+
+	return infos
+}
+
+// dependencyGraph maps normalized names of installed packages to their metadata
+type dependencyGraph map[string]*PackageInfo
+
+// requires returns the normalized names of the installed packages name requires
+func (g dependencyGraph) requires(name string) []string {
+	info := g[name]
+	if info == nil {
+		return nil
+	}
+
+	var deps []string
+	for _, dep := range info.Requires {
+		if key := normalizePackageName(strings.TrimSpace(dep)); g[key] != nil {
+			deps = append(deps, key)
+		}
+	}
+	return deps
+}
+
+// closure returns every installed package reachable from roots, including the roots
+func (g dependencyGraph) closure(roots []string) map[string]bool {
+	reached := make(map[string]bool)
+	queue := append([]string{}, roots...)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reached[name] || g[name] == nil {
+			continue
+		}
+		reached[name] = true
+		queue = append(queue, g.requires(name)...)
+	}
+	return reached
+}
+
+// requested returns the installed packages the user asked for by name, as
+// recorded by the REQUESTED marker pip writes to their dist-info directory
+func (g dependencyGraph) requested() []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, info := range g {
+		if info.Location == "" || seen[info.Location] {
+			continue
+		}
+		seen[info.Location] = true
+
+		entries, err := os.ReadDir(info.Location)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".dist-info") {
+				continue
+			}
+			name, _ := splitNameVersion(strings.TrimSuffix(entry.Name(), ".dist-info"))
+			if g[normalizePackageName(name)] == nil {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(info.Location, entry.Name(), "REQUESTED")); err == nil {
+				names = append(names, normalizePackageName(name))
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// orphans returns the dependencies of targets that become unused once the
+// targets are removed, sorted by name. Packages in keep, and everything
+// they require, are never orphans.
+func (g dependencyGraph) orphans(targets []string, keep []string) []string {
+	removed := make(map[string]bool)
+	for _, target := range targets {
+		removed[normalizePackageName(target)] = true
+	}
+
+	// Everything the targets pull in is a candidate
+	var deps []string
+	for target := range removed {
+		deps = append(deps, g.requires(target)...)
+	}
+	candidates := g.closure(deps)
+	for target := range removed {
+		delete(candidates, target)
+	}
+
+	// Packages that stay installed keep whatever they require
+	var roots []string
+	for name := range g {
+		if !removed[name] && !candidates[name] {
+			roots = append(roots, name)
+		}
+	}
+	for _, name := range keep {
+		if key := normalizePackageName(name); !removed[key] {
+			roots = append(roots, key)
+		}
+	}
+	required := g.closure(roots)
+
+	var orphans []string
+	for name := range candidates {
+		if !required[name] && !removed[name] {
+			orphans = append(orphans, g[name].Name)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// requirementFileNames returns the package names listed in a requirements
+// file, following nested "-r" includes. Editable installs, local paths and
+// URLs are skipped.
+func requirementFileNames(path string) ([]string, error) {
+	requirements, err := readRequirements(path, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(requirements))
+	for _, req := range requirements {
+		names = append(names, req.name)
+	}
+	return names, nil
+}
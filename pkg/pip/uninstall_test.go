@@ -0,0 +1,202 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// showOutput formats "pip show" output for several packages
+func showOutput(requires map[string]string) string {
+	var names []string
+	for name := range requires {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sections []string
+	for _, name := range names {
+		sections = append(sections, "Name: "+name+"\nVersion: 1.0\nRequires: "+requires[name]+"\nRequired-by: ")
+	}
+	return strings.Join(sections, "\n---\n") + "\n"
+}
+
+// newDependencyGraph builds a graph from "pip show" output
+func newDependencyGraph(requires map[string]string) dependencyGraph {
+	graph := make(dependencyGraph)
+	for _, info := range NewManager(nil).parseShowOutputs(showOutput(requires)) {
+		graph[normalizePackageName(info.Name)] = info
+	}
+	return graph
+}
+
+func TestDependencyGraphOrphans(t *testing.T) {
+	graph := newDependencyGraph(map[string]string{
+		"requests":           "certifi, charset-normalizer, idna, urllib3",
+		"httpx":              "certifi, idna, sniffio",
+		"certifi":            "",
+		"charset-normalizer": "",
+		"idna":               "",
+		"urllib3":            "",
+		"sniffio":            "",
+		"black":              "click, Pathspec",
+		"click":              "",
+		"pathspec":           "",
+		"build":              "packaging, setuptools, wheel",
+		"packaging":          "",
+		"pip":                "",
+		"setuptools":         "",
+		"wheel":              "",
+	})
+
+	tests := []struct {
+		name    string
+		targets []string
+		keep    []string
+		want    []string
+	}{
+		{"shared dependencies stay", []string{"requests"}, nil, []string{"charset-normalizer", "urllib3"}},
+		{"keep list", []string{"requests"}, []string{"URLLib3"}, []string{"charset-normalizer"}},
+		{"several targets", []string{"requests", "httpx"}, nil, []string{"certifi", "charset-normalizer", "idna", "sniffio", "urllib3"}},
+		{"normalized names", []string{"Black"}, nil, []string{"click", "pathspec"}},
+		{"protected packages", []string{"build"}, nil, []string{"packaging"}},
+		{"leaf package", []string{"sniffio"}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := graph.orphans(tt.targets, append(append([]string{}, protectedPackages...), tt.keep...))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orphans(%v) = %v, want %v", tt.targets, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUninstallPackagesAutoremove(t *testing.T) {
+	dir := t.TempDir()
+	requirements := filepath.Join(dir, "requirements.txt")
+	os.WriteFile(requirements, []byte("# tools\nblack>=23 # formatter\n-e ./local\n"), 0644)
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "list", "--format=json").Respond(
+		`[{"name": "requests", "version": "2.31.0"}, {"name": "urllib3", "version": "2.0.7"}, `+
+			`{"name": "black", "version": "23.1"}, {"name": "click", "version": "8.1.7"}, `+
+			`{"name": "flask", "version": "3.0"}, {"name": "pip", "version": "23.3"}]`, "", 0)
+	runner.On("python", "-m", "pip", "show", "...").Respond(showOutput(map[string]string{
+		"requests": "urllib3",
+		"urllib3":  "",
+		"black":    "click",
+		"click":    "",
+		"flask":    "click",
+		"pip":      "",
+	}), "", 0)
+	runner.On("python", "-m", "pip", "uninstall", "-y", "requests", "urllib3", "-r", requirements).Respond(
+		"Found existing installation: requests 2.31.0\nSuccessfully uninstalled requests-2.31.0\n"+
+			"Successfully uninstalled urllib3-2.0.7\nSuccessfully uninstalled black-23.1\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	results, err := manager.UninstallPackages([]string{"requests"}, &UninstallOptions{
+		Requirements: []string{requirements},
+		Autoremove:   true,
+	})
+	if err != nil {
+		t.Fatalf("UninstallPackages() error: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		if !result.Success {
+			t.Errorf("%s failed: %v", result.PackageName, result.Error)
+		}
+		got = append(got, result.PackageName)
+		if result.Autoremoved != (result.PackageName == "urllib3") {
+			t.Errorf("%s Autoremoved = %v", result.PackageName, result.Autoremoved)
+		}
+	}
+	if want := []string{"requests", "black", "urllib3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+}
+
+func TestUninstallPackagesAutoremoveKeepsRequested(t *testing.T) {
+	// After "pip install requests urllib3", urllib3 is both a dependency
+	// of requests and requested by the user
+	site := t.TempDir()
+	for _, dist := range []string{"requests-2.31.0", "urllib3-2.0.7", "idna-3.6"} {
+		os.MkdirAll(filepath.Join(site, dist+".dist-info"), 0755)
+	}
+	os.WriteFile(filepath.Join(site, "requests-2.31.0.dist-info", "REQUESTED"), nil, 0644)
+	os.WriteFile(filepath.Join(site, "urllib3-2.0.7.dist-info", "REQUESTED"), nil, 0644)
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "list", "--format=json").Respond(
+		`[{"name": "requests", "version": "2.31.0"}, {"name": "urllib3", "version": "2.0.7"}, {"name": "idna", "version": "3.6"}]`, "", 0)
+	runner.On("python", "-m", "pip", "show", "...").Respond(
+		"Name: requests\nVersion: 2.31.0\nLocation: "+site+"\nRequires: idna, urllib3\nRequired-by: \n---\n"+
+			"Name: urllib3\nVersion: 2.0.7\nLocation: "+site+"\nRequires: \nRequired-by: requests\n---\n"+
+			"Name: idna\nVersion: 3.6\nLocation: "+site+"\nRequires: \nRequired-by: requests\n", "", 0)
+	runner.On("python", "-m", "pip", "uninstall", "-y", "requests", "idna").Respond(
+		"Successfully uninstalled requests-2.31.0\nSuccessfully uninstalled idna-3.6\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	results, err := manager.UninstallPackages([]string{"requests"}, &UninstallOptions{Autoremove: true})
+	if err != nil {
+		t.Fatalf("UninstallPackages() error: %v", err)
+	}
+
+	var got []string
+	for _, result := range results {
+		got = append(got, result.PackageName)
+	}
+	if want := []string{"requests", "idna"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %v, want %v", got, want)
+	}
+}
+
+func TestUninstallPackagesNotInstalled(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "uninstall", "-y", "requests", "nosuchpkg").Respond(
+		"Successfully uninstalled requests-2.31.0\n", "WARNING: Skipping nosuchpkg as it is not installed.\n", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	results, err := manager.UninstallPackages([]string{"requests", "nosuchpkg"}, nil)
+	if err != nil {
+		t.Fatalf("UninstallPackages() error: %v", err)
+	}
+	if !results[0].Success || results[1].Success || !IsErrorType(results[1].Error, ErrorTypePackageNotFound) {
+		t.Errorf("results = %+v, %+v", results[0], results[1])
+	}
+
+	if _, err := manager.UninstallPackages(nil, nil); err == nil {
+		t.Error("UninstallPackages() with nothing to remove should fail")
+	}
+	if _, err := manager.UninstallPackages([]string{""}, nil); err == nil {
+		t.Error("UninstallPackages() with an empty name should fail")
+	}
+}
+
+func TestRequirementFileNames(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "base.txt"), []byte("Django==4.2\n-r requirements.txt\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte(
+		"-r base.txt\nrequests[socks]>=2.0 ; python_version >= '3.8'\n--index-url https://example.com/simple\n"+
+			"./local-pkg\nhttps://example.com/pkg.tar.gz\nmylib @ https://example.com/mylib-1.0.tar.gz\n"), 0644)
+
+	names, err := requirementFileNames(filepath.Join(dir, "requirements.txt"))
+	if err != nil {
+		t.Fatalf("requirementFileNames() error: %v", err)
+	}
+	if want := []string{"django", "requests", "mylib"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,255 @@
+package pip
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UpgradeKind classifies a version bump by the first release segment that changes
+type UpgradeKind string
+
+const (
+	UpgradePatch UpgradeKind = "patch"
+	UpgradeMinor UpgradeKind = "minor"
+	UpgradeMajor UpgradeKind = "major"
+	// UpgradeUnknown is used when either version is not valid PEP 440
+	UpgradeUnknown UpgradeKind = "unknown"
+)
+
+// upgradeRank orders upgrade kinds from least to most disruptive
+var upgradeRank = map[UpgradeKind]int{
+	UpgradePatch:   1,
+	UpgradeMinor:   2,
+	UpgradeMajor:   3,
+	UpgradeUnknown: 4,
+}
+
+// UpgradePolicy selects which outdated packages an UpgradePlan upgrades
+type UpgradePolicy struct {
+	// MaxKind is the largest bump allowed, e.g. UpgradePatch for "patch
+	// only". Empty allows every valid bump; UpgradeUnknown also allows
+	// versions that are not PEP 440.
+	MaxKind UpgradeKind `json:"max_kind,omitempty"`
+
+	// Include limits the plan to these packages when not empty
+	Include []string `json:"include,omitempty"`
+
+	// Exclude lists packages that are never upgraded
+	Exclude []string `json:"exclude,omitempty"`
+
+	// AllowPrereleases permits upgrading to a pre-release version
+	AllowPrereleases bool `json:"allow_prereleases,omitempty"`
+}
+
+// PackageUpgrade is a single version bump in an UpgradePlan
+type PackageUpgrade struct {
+	Name   string      `json:"name"`
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Kind   UpgradeKind `json:"kind"`
+	Reason string      `json:"reason,omitempty"` // why the upgrade was skipped
+}
+
+// String returns e.g. "requests 2.28.0 -> 2.31.0 (minor)"
+func (u *PackageUpgrade) String() string {
+	return fmt.Sprintf("%s %s -> %s (%s)", u.Name, u.From, u.To, u.Kind)
+}
+
+// UpgradePlan lists the upgrades a policy allows and those it holds back
+type UpgradePlan struct {
+	Upgrades []*PackageUpgrade `json:"upgrades"`
+	Skipped  []*PackageUpgrade `json:"skipped,omitempty"`
+}
+
+// ListOutdated lists installed packages that have a newer release on the
+// index, with LatestVersion and LatestFiletype set
+func (m *Manager) ListOutdated() ([]*Package, error) {
+	m.logDebug("Listing outdated packages")
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return nil, ErrPipNotInstalled
+	}
+
+	args := []string{"list", "--outdated", "--format=json"}
+	output, err := m.executePipCommandWithOutput(pipPath, args)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := parseJSONPackages(output)
+	if err != nil {
+		return nil, &PipError{
+			Type:    "invalid_output",
+			Message: fmt.Sprintf("failed to parse pip list output: %v", err),
+			Output:  output,
+		}
+	}
+	return packages, nil
+}
+
+// parseJSONPackages decodes the JSON array printed by "pip list --format=json".
+// Warnings pip prints around it, such as the new release notice, are ignored.
+func parseJSONPackages(output string) ([]*Package, error) {
+	var lastErr error
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		var packages []*Package
+		if lastErr = json.Unmarshal([]byte(line), &packages); lastErr == nil {
+			return packages, nil
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no JSON package list in output")
+	}
+	return nil, lastErr
+}
+
+// ClassifyUpgrade reports whether moving from one version to another is a
+// major, minor or patch bump. Missing release segments count as zero, so
+// "1.2" to "1.2.1" is a patch; pre-, post- and dev-release changes within
+// the same release are patches. It returns UpgradeUnknown when either
+// version is not valid PEP 440.
+func ClassifyUpgrade(from, to string) UpgradeKind {
+	fromVersion, err := ParseVersion(from)
+	if err != nil {
+		return UpgradeUnknown
+	}
+	toVersion, err := ParseVersion(to)
+	if err != nil {
+		return UpgradeUnknown
+	}
+
+	if fromVersion.Epoch != toVersion.Epoch {
+		return UpgradeMajor
+	}
+	if releaseSegment(fromVersion, 0) != releaseSegment(toVersion, 0) {
+		return UpgradeMajor
+	}
+	if releaseSegment(fromVersion, 1) != releaseSegment(toVersion, 1) {
+		return UpgradeMinor
+	}
+	return UpgradePatch
+}
+
+// releaseSegment returns release segment i, or 0 when the version is shorter
+func releaseSegment(v *Version, i int) int {
+	if i < len(v.Release) {
+		return v.Release[i]
+	}
+	return 0
+}
+
+// NewUpgradePlan applies policy to outdated packages as returned by
+// ListOutdated. A nil policy allows every upgrade to a final release.
+func NewUpgradePlan(outdated []*Package, policy *UpgradePolicy) *UpgradePlan {
+	if policy == nil {
+		policy = &UpgradePolicy{}
+	}
+
+	plan := &UpgradePlan{Upgrades: []*PackageUpgrade{}}
+	for _, pkg := range outdated {
+		if pkg == nil || pkg.LatestVersion == "" {
+			continue
+		}
+
+		upgrade := &PackageUpgrade{
+			Name: pkg.Name,
+			From: pkg.Version,
+			To:   pkg.LatestVersion,
+			Kind: ClassifyUpgrade(pkg.Version, pkg.LatestVersion),
+		}
+
+		if upgrade.Reason = policy.skipReason(upgrade); upgrade.Reason != "" {
+			plan.Skipped = append(plan.Skipped, upgrade)
+		} else {
+			plan.Upgrades = append(plan.Upgrades, upgrade)
+		}
+	}
+
+	sort.Slice(plan.Upgrades, func(i, j int) bool {
+		return normalizePackageName(plan.Upgrades[i].Name) < normalizePackageName(plan.Upgrades[j].Name)
+	})
+	sort.Slice(plan.Skipped, func(i, j int) bool {
+		return normalizePackageName(plan.Skipped[i].Name) < normalizePackageName(plan.Skipped[j].Name)
+	})
+	return plan
+}
+
+// skipReason returns why the policy holds back an upgrade, or "" to allow it
+func (p *UpgradePolicy) skipReason(upgrade *PackageUpgrade) string {
+	name := normalizePackageName(upgrade.Name)
+
+	if len(p.Include) > 0 && !containsPackageName(p.Include, name) {
+		return "not included by policy"
+	}
+	if containsPackageName(p.Exclude, name) {
+		return "excluded by policy"
+	}
+
+	if upgrade.Kind == UpgradeUnknown {
+		if p.MaxKind != UpgradeUnknown {
+			return "version is not PEP 440"
+		}
+		return ""
+	}
+
+	if to, err := ParseVersion(upgrade.To); err == nil && to.IsPrerelease() && !p.AllowPrereleases {
+		return "pre-release"
+	}
+	if from, err := ParseVersion(upgrade.From); err == nil {
+		if to, err := ParseVersion(upgrade.To); err == nil && to.Compare(from) <= 0 {
+			return "not newer than the installed version"
+		}
+	}
+
+	if p.MaxKind != "" && upgradeRank[upgrade.Kind] > upgradeRank[p.MaxKind] {
+		return fmt.Sprintf("%s upgrade exceeds %s policy", upgrade.Kind, p.MaxKind)
+	}
+	return ""
+}
+
+// containsPackageName reports whether names contains normalized, comparing
+// names as described in PEP 503
+func containsPackageName(names []string, normalized string) bool {
+	for _, name := range names {
+		if normalizePackageName(name) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanUpgrades lists outdated packages and applies policy to them
+func (m *Manager) PlanUpgrades(policy *UpgradePolicy) (*UpgradePlan, error) {
+	outdated, err := m.ListOutdated()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := NewUpgradePlan(outdated, policy)
+	m.logInfo("Upgrade plan: %d upgrades, %d skipped", len(plan.Upgrades), len(plan.Skipped))
+	return plan, nil
+}
+
+// ApplyUpgradePlan installs every upgrade in the plan in a single pip run,
+// pinning each package to its planned version
+func (m *Manager) ApplyUpgradePlan(plan *UpgradePlan) ([]*InstallResult, error) {
+	if plan == nil || len(plan.Upgrades) == 0 {
+		return nil, nil
+	}
+
+	pkgs := make([]*PackageSpec, len(plan.Upgrades))
+	for i, upgrade := range plan.Upgrades {
+		pkgs[i] = &PackageSpec{Name: upgrade.Name, Version: "==" + upgrade.To}
+	}
+
+	return m.InstallPackages(pkgs, nil)
+}
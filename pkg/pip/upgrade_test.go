@@ -0,0 +1,151 @@
+package pip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListOutdated(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "list", "--outdated", "--format=json").Respond(
+		`[{"name": "requests", "version": "2.28.0", "latest_version": "2.31.0", "latest_filetype": "wheel"}, `+
+			`{"name": "Django", "version": "3.2.20", "latest_version": "4.2.5", "latest_filetype": "sdist"}]`+"\n",
+		"WARNING: There was an error checking the latest version of pip.\n", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	packages, err := manager.ListOutdated()
+	if err != nil {
+		t.Fatalf("ListOutdated() error: %v", err)
+	}
+
+	want := []*Package{
+		{Name: "requests", Version: "2.28.0", LatestVersion: "2.31.0", LatestFiletype: "wheel"},
+		{Name: "Django", Version: "3.2.20", LatestVersion: "4.2.5", LatestFiletype: "sdist"},
+	}
+	if !reflect.DeepEqual(packages, want) {
+		t.Errorf("ListOutdated() = %+v, want %+v", packages, want)
+	}
+}
+
+func TestListOutdatedInvalidOutput(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "list", "...").Respond("not json\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	if _, err := manager.ListOutdated(); err == nil {
+		t.Error("ListOutdated() should fail on unparseable output")
+	}
+}
+
+func TestClassifyUpgrade(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     UpgradeKind
+	}{
+		{"1.2.3", "1.2.4", UpgradePatch},
+		{"1.2", "1.2.1", UpgradePatch},
+		{"1.2.3", "1.3.0", UpgradeMinor},
+		{"1.2.3", "2.0.0", UpgradeMajor},
+		{"0.9", "1.0", UpgradeMajor},
+		{"1.0", "1!1.0", UpgradeMajor},
+		{"2.0.0rc1", "2.0.0", UpgradePatch},
+		{"1.0.0", "1.0.0.post1", UpgradePatch},
+		{"2023.7.22", "2024.2.2", UpgradeMajor},
+		{"1.0", "not-a-version", UpgradeUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyUpgrade(tt.from, tt.to); got != tt.want {
+			t.Errorf("ClassifyUpgrade(%q, %q) = %s, want %s", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestNewUpgradePlan(t *testing.T) {
+	outdated := []*Package{
+		{Name: "urllib3", Version: "1.26.5", LatestVersion: "1.26.18"},
+		{Name: "requests", Version: "2.28.0", LatestVersion: "2.31.0"},
+		{Name: "Django", Version: "3.2.20", LatestVersion: "4.2.5"},
+		{Name: "zope.interface", Version: "6.0", LatestVersion: "6.0.1"},
+		{Name: "numpy", Version: "1.26.0", LatestVersion: "1.26.1rc1"},
+		{Name: "legacy", Version: "1.0", LatestVersion: "latest"},
+	}
+
+	kinds := func(upgrades []*PackageUpgrade) map[string]string {
+		result := make(map[string]string)
+		for _, upgrade := range upgrades {
+			result[upgrade.Name] = string(upgrade.Kind) + ":" + upgrade.Reason
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		policy   *UpgradePolicy
+		upgrades []string
+	}{
+		{"default", nil, []string{"Django", "requests", "urllib3", "zope.interface"}},
+		{"patch only", &UpgradePolicy{MaxKind: UpgradePatch}, []string{"urllib3", "zope.interface"}},
+		{"minor", &UpgradePolicy{MaxKind: UpgradeMinor, Exclude: []string{"Zope_Interface"}}, []string{"requests", "urllib3"}},
+		{"include", &UpgradePolicy{Include: []string{"django", "numpy"}, AllowPrereleases: true}, []string{"Django", "numpy"}},
+		{"unknown", &UpgradePolicy{MaxKind: UpgradeUnknown, Include: []string{"legacy"}}, []string{"legacy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewUpgradePlan(outdated, tt.policy)
+
+			var names []string
+			for _, upgrade := range plan.Upgrades {
+				names = append(names, upgrade.Name)
+			}
+			if !reflect.DeepEqual(names, tt.upgrades) {
+				t.Errorf("upgrades = %v, want %v", names, tt.upgrades)
+			}
+			if len(plan.Upgrades)+len(plan.Skipped) != len(outdated) {
+				t.Errorf("plan lost packages: %v %v", kinds(plan.Upgrades), kinds(plan.Skipped))
+			}
+			for _, skipped := range plan.Skipped {
+				if skipped.Reason == "" {
+					t.Errorf("skipped %s has no reason", skipped.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyUpgradePlan(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "list", "--outdated", "--format=json").Respond(
+		`[{"name": "requests", "version": "2.28.0", "latest_version": "2.31.0", "latest_filetype": "wheel"}, `+
+			`{"name": "urllib3", "version": "1.26.5", "latest_version": "2.0.7", "latest_filetype": "wheel"}]`, "", 0)
+	runner.On("python", "-m", "pip", "install", "requests==2.31.0").Respond(
+		"Successfully installed requests-2.31.0\n", "", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	plan, err := manager.PlanUpgrades(&UpgradePolicy{MaxKind: UpgradeMinor})
+	if err != nil {
+		t.Fatalf("PlanUpgrades() error: %v", err)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].String() != "urllib3 1.26.5 -> 2.0.7 (major)" {
+		t.Errorf("skipped = %v", plan.Skipped)
+	}
+
+	results, err := manager.ApplyUpgradePlan(plan)
+	if err != nil {
+		t.Fatalf("ApplyUpgradePlan() error: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success || results[0].Version != "2.31.0" {
+		t.Errorf("results = %+v", results)
+	}
+
+	if results, err := manager.ApplyUpgradePlan(&UpgradePlan{}); results != nil || err != nil {
+		t.Errorf("empty plan = %v, %v", results, err)
+	}
+}
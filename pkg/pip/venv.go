@@ -3,7 +3,6 @@ package pip
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -77,11 +76,11 @@ func (m *Manager) CreateVenv(path string) error {
 func (vm *VenvManager) createWithVenv(pythonPath, path string) error {
 	vm.manager.logDebug("Trying to create venv using python -m venv")
 
-	cmd := exec.CommandContext(vm.manager.ctx, pythonPath, "-m", "venv", path)
-	output, err := cmd.CombinedOutput()
+	cmd := &Command{Name: pythonPath, Args: []string{"-m", "venv", path}}
+	result, err := vm.manager.runPlanned(cmd, "create virtual environment")
 
 	if err != nil {
-		vm.manager.logDebug("venv creation failed: %v, output: %s", err, string(output))
+		vm.manager.logDebug("venv creation failed: %v, output: %s", err, result.Output)
 		return err
 	}
 
@@ -92,11 +91,11 @@ func (vm *VenvManager) createWithVenv(pythonPath, path string) error {
 func (vm *VenvManager) createWithVirtualenv(pythonPath, path string) error {
 	vm.manager.logDebug("Trying to create venv using python -m virtualenv")
 
-	cmd := exec.CommandContext(vm.manager.ctx, pythonPath, "-m", "virtualenv", path)
-	output, err := cmd.CombinedOutput()
+	cmd := &Command{Name: pythonPath, Args: []string{"-m", "virtualenv", path}}
+	result, err := vm.manager.runPlanned(cmd, "create virtual environment")
 
 	if err != nil {
-		vm.manager.logDebug("virtualenv creation failed: %v, output: %s", err, string(output))
+		vm.manager.logDebug("virtualenv creation failed: %v, output: %s", err, result.Output)
 		return err
 	}
 
@@ -108,15 +107,15 @@ func (vm *VenvManager) createWithVirtualenvCommand(path string) error {
 	vm.manager.logDebug("Trying to create venv using virtualenv command")
 
 	// Check if virtualenv command exists
-	if _, err := exec.LookPath("virtualenv"); err != nil {
+	if _, err := vm.manager.lookPath("virtualenv"); err != nil {
 		return err
 	}
 
-	cmd := exec.CommandContext(vm.manager.ctx, "virtualenv", path)
-	output, err := cmd.CombinedOutput()
+	cmd := &Command{Name: "virtualenv", Args: []string{path}}
+	result, err := vm.manager.runPlanned(cmd, "create virtual environment")
 
 	if err != nil {
-		vm.manager.logDebug("virtualenv command failed: %v, output: %s", err, string(output))
+		vm.manager.logDebug("virtualenv command failed: %v, output: %s", err, result.Output)
 		return err
 	}
 
@@ -125,6 +124,10 @@ func (vm *VenvManager) createWithVirtualenvCommand(path string) error {
 
 // ActivateVenv activates a virtual environment
 func (m *Manager) ActivateVenv(path string) error {
+	if m.scoped {
+		return errScopedManager
+	}
+
 	if path == "" {
 		return &PipError{
 			Type:    "invalid_path",
@@ -151,22 +154,11 @@ func (m *Manager) ActivateVenv(path string) error {
 		}
 	}
 
-	// Update environment variables
-	venvBinPath := m.getVenvBinPath(path)
-	pythonPath := filepath.Join(venvBinPath, m.getPythonExecutableName())
-	pipPath := filepath.Join(venvBinPath, m.getPipExecutableName())
-
-	// Update manager configuration
-	m.config.PythonPath = pythonPath
-	m.config.PipPath = pipPath
-
-	// Set environment variables
-	if m.config.Environment == nil {
-		m.config.Environment = make(map[string]string)
-	}
-
-	m.config.Environment["VIRTUAL_ENV"] = path
-	m.config.Environment["PATH"] = venvBinPath + string(os.PathListSeparator) + os.Getenv("PATH")
+	// Update manager configuration; the config is replaced rather than
+	// modified so concurrent operations keep a consistent snapshot
+	m.mu.Lock()
+	m.config = m.venvConfig(m.config, path)
+	m.mu.Unlock()
 
 	m.logInfo("Virtual environment activated: %s", path)
 	return nil
@@ -176,16 +168,26 @@ func (m *Manager) ActivateVenv(path string) error {
 func (m *Manager) DeactivateVenv() error {
 	m.logInfo("Deactivating virtual environment")
 
+	if m.scoped {
+		return errScopedManager
+	}
+
+	m.mu.Lock()
+	config := copyConfig(m.config)
+
 	// Reset paths
-	m.config.PythonPath = ""
-	m.config.PipPath = ""
+	config.PythonPath = ""
+	config.PipPath = ""
 
 	// Remove virtual environment variables
-	if m.config.Environment != nil {
-		delete(m.config.Environment, "VIRTUAL_ENV")
-		delete(m.config.Environment, "PATH")
+	if config.Environment != nil {
+		delete(config.Environment, "VIRTUAL_ENV")
+		delete(config.Environment, "PATH")
 	}
 
+	m.config = config
+	m.mu.Unlock()
+
 	m.logInfo("Virtual environment deactivated")
 	return nil
 }
@@ -210,7 +212,7 @@ func (m *Manager) RemoveVenv(path string) error {
 	}
 
 	// Remove directory
-	if err := os.RemoveAll(path); err != nil {
+	if err := m.removeAll(path); err != nil {
 		return &PipError{
 			Type:    "venv_removal_failed",
 			Message: fmt.Sprintf("failed to remove virtual environment: %v", err),
@@ -251,8 +253,12 @@ func (m *Manager) GetVenvInfo(path string) (*VenvInfo, error) {
 		info.CreatedAt = stat.ModTime()
 	}
 
-	// Get Python version
-	if version, err := m.getPythonVersionInVenv(info.PythonPath); err == nil {
+	// Read the real paths from the interpreter, falling back to its version output
+	if details, err := m.InspectInterpreter(info.PythonPath); err == nil {
+		info.PythonVersion = details.Version
+		info.SitePackages = details.Paths.Purelib
+		info.ScriptsPath = details.Paths.Scripts
+	} else if version, err := m.getPythonVersionInVenv(info.PythonPath); err == nil {
 		info.PythonVersion = version
 	}
 
@@ -279,11 +285,12 @@ func (m *Manager) isVenvValid(path string) bool {
 
 // isVenvActive checks if a virtual environment is currently active
 func (m *Manager) isVenvActive(path string) bool {
-	if m.config.Environment == nil {
+	config := m.currentConfig()
+	if config.Environment == nil {
 		return false
 	}
 
-	virtualEnv, exists := m.config.Environment["VIRTUAL_ENV"]
+	virtualEnv, exists := config.Environment["VIRTUAL_ENV"]
 	if !exists {
 		return false
 	}
@@ -304,10 +311,18 @@ func (m *Manager) isVenvActive(path string) bool {
 
 // getVenvBinPath returns the bin/Scripts directory path for a virtual environment
 func (m *Manager) getVenvBinPath(venvPath string) string {
+	preferred, other := "bin", "Scripts"
 	if runtime.GOOS == "windows" {
-		return filepath.Join(venvPath, "Scripts")
+		preferred, other = other, preferred
+	}
+
+	// Some interpreters, such as MSYS2 Python on Windows, use the other layout
+	if _, err := os.Stat(filepath.Join(venvPath, preferred)); os.IsNotExist(err) {
+		if info, err := os.Stat(filepath.Join(venvPath, other)); err == nil && info.IsDir() {
+			return filepath.Join(venvPath, other)
+		}
 	}
-	return filepath.Join(venvPath, "bin")
+	return filepath.Join(venvPath, preferred)
 }
 
 // getActivateScriptPath returns the activation script path for a virtual environment
@@ -352,14 +367,13 @@ func (m *Manager) getPipExecutableName() string {
 
 // getPythonVersionInVenv gets the Python version in a virtual environment
 func (m *Manager) getPythonVersionInVenv(pythonPath string) (string, error) {
-	cmd := exec.CommandContext(m.ctx, pythonPath, "--version")
-	output, err := cmd.Output()
+	result, err := m.runCommand(&Command{Name: pythonPath, Args: []string{"--version"}})
 	if err != nil {
 		return "", err
 	}
 
 	// Parse version from output (e.g., "Python 3.9.7")
-	versionStr := strings.TrimSpace(string(output))
+	versionStr := strings.TrimSpace(result.Stdout)
 	parts := strings.Fields(versionStr)
 	if len(parts) >= 2 {
 		return parts[1], nil
@@ -0,0 +1,427 @@
+package pip
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed PEP 440 version
+type Version struct {
+	Epoch   int
+	Release []int
+	PreKind string // "a", "b" or "rc"; empty for final releases
+	Pre     int
+	Post    int // -1 when there is no post-release segment
+	Dev     int // -1 when there is no dev-release segment
+	Local   string
+
+	original string
+}
+
+// versionRegex is the PEP 440 version pattern from the specification's appendix
+var versionRegex = regexp.MustCompile(`(?i)^\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?P<pre>[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?))?` +
+	`(?P<dev>[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?\s*$`)
+
+// ParseVersion parses a PEP 440 version string such as "1.2.3rc1.post2"
+func ParseVersion(s string) (*Version, error) {
+	match := versionRegex.FindStringSubmatch(s)
+	if match == nil {
+		return nil, fmt.Errorf("invalid version: %q", s)
+	}
+
+	group := func(name string) string {
+		return match[versionRegex.SubexpIndex(name)]
+	}
+	number := func(value string) int {
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+
+	v := &Version{Post: -1, Dev: -1, original: s}
+
+	if epoch := group("epoch"); epoch != "" {
+		v.Epoch = number(epoch)
+	}
+	for _, part := range strings.Split(group("release"), ".") {
+		v.Release = append(v.Release, number(part))
+	}
+
+	if group("pre") != "" {
+		switch strings.ToLower(group("pre_l")) {
+		case "a", "alpha":
+			v.PreKind = "a"
+		case "b", "beta":
+			v.PreKind = "b"
+		default:
+			v.PreKind = "rc"
+		}
+		v.Pre = number(group("pre_n"))
+	}
+
+	if group("post") != "" {
+		if n := group("post_n1"); n != "" {
+			v.Post = number(n)
+		} else {
+			v.Post = number(group("post_n2"))
+		}
+	}
+
+	if group("dev") != "" {
+		v.Dev = number(group("dev_n"))
+	}
+
+	v.Local = strings.ToLower(strings.NewReplacer("-", ".", "_", ".").Replace(group("local")))
+
+	return v, nil
+}
+
+// MustParseVersion is like ParseVersion but panics on invalid input
+func MustParseVersion(s string) *Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String returns the normalized form of the version
+func (v *Version) String() string {
+	var b strings.Builder
+	if v.Epoch != 0 {
+		fmt.Fprintf(&b, "%d!", v.Epoch)
+	}
+	for i, part := range v.Release {
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(strconv.Itoa(part))
+	}
+	if v.PreKind != "" {
+		fmt.Fprintf(&b, "%s%d", v.PreKind, v.Pre)
+	}
+	if v.Post >= 0 {
+		fmt.Fprintf(&b, ".post%d", v.Post)
+	}
+	if v.Dev >= 0 {
+		fmt.Fprintf(&b, ".dev%d", v.Dev)
+	}
+	if v.Local != "" {
+		b.WriteString("+" + v.Local)
+	}
+	return b.String()
+}
+
+// IsPrerelease reports whether the version is a pre- or development release
+func (v *Version) IsPrerelease() bool {
+	return v.PreKind != "" || v.Dev >= 0
+}
+
+// IsPostRelease reports whether the version has a post-release segment
+func (v *Version) IsPostRelease() bool {
+	return v.Post >= 0
+}
+
+// Public returns the version without its local segment
+func (v *Version) Public() *Version {
+	public := *v
+	public.Local = ""
+	return &public
+}
+
+// BaseVersion returns the epoch and release segments only
+func (v *Version) BaseVersion() *Version {
+	return &Version{Epoch: v.Epoch, Release: v.Release, Post: -1, Dev: -1}
+}
+
+// Compare returns -1, 0 or 1 as v sorts before, equal to or after other
+// according to PEP 440
+func (v *Version) Compare(other *Version) int {
+	if c := compareInts(v.Epoch, other.Epoch); c != 0 {
+		return c
+	}
+	if c := compareRelease(v.Release, other.Release); c != 0 {
+		return c
+	}
+	if c := compareInts(v.preKey(), other.preKey()); c != 0 {
+		return c
+	}
+	if v.PreKind != "" && v.PreKind == other.PreKind {
+		if c := compareInts(v.Pre, other.Pre); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(v.Post, other.Post); c != 0 {
+		return c
+	}
+	if c := compareInts(v.devKey(), other.devKey()); c != 0 {
+		return c
+	}
+	return compareLocal(v.Local, other.Local)
+}
+
+// preKey orders the pre-release phase: dev-only releases sort before
+// alpha, alpha before beta before rc, and final releases last
+func (v *Version) preKey() int {
+	switch {
+	case v.PreKind == "" && v.Post < 0 && v.Dev >= 0:
+		return -1
+	case v.PreKind == "a":
+		return 0
+	case v.PreKind == "b":
+		return 1
+	case v.PreKind == "rc":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// devKey sorts development releases before the release they precede
+func (v *Version) devKey() int {
+	if v.Dev < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return v.Dev
+}
+
+// compareInts compares two ints
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareRelease compares release segments, ignoring trailing zeros
+func compareRelease(a, b []int) int {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if c := compareInts(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareLocal compares local version labels. Numeric segments sort after
+// alphanumeric ones and a version without a label sorts first.
+func compareLocal(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return -1
+	}
+	if b == "" {
+		return 1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, xErr := strconv.Atoi(as[i])
+		y, yErr := strconv.Atoi(bs[i])
+		switch {
+		case xErr == nil && yErr == nil:
+			if c := compareInts(x, y); c != 0 {
+				return c
+			}
+		case xErr == nil:
+			return 1
+		case yErr == nil:
+			return -1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(as), len(bs))
+}
+
+// Specifier is a single PEP 440 version clause such as ">=3.10"
+type Specifier struct {
+	Operator string
+	Version  string
+
+	version  *Version
+	wildcard bool // "==X.*" or "!=X.*"
+}
+
+// SpecifierSet is a comma-separated list of specifiers that must all match
+type SpecifierSet []*Specifier
+
+// specifierRegex splits a clause into operator and version
+var specifierRegex = regexp.MustCompile(`^\s*(~=|===|==|!=|<=|>=|<|>)\s*(\S+)\s*$`)
+
+// ParseSpecifierSet parses a specifier list such as ">=3.10,<3.13".
+// An empty string matches every version. A bare version such as "3.11" is
+// treated as "==3.11.*".
+func ParseSpecifierSet(s string) (SpecifierSet, error) {
+	var set SpecifierSet
+	for _, clause := range strings.Split(s, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		var spec *Specifier
+		if match := specifierRegex.FindStringSubmatch(clause); match != nil {
+			spec = &Specifier{Operator: match[1], Version: match[2]}
+		} else if _, err := ParseVersion(clause); err == nil {
+			spec = &Specifier{Operator: "==", Version: clause + ".*"}
+		} else {
+			return nil, fmt.Errorf("invalid version specifier: %q", clause)
+		}
+
+		if spec.Operator != "===" {
+			version := spec.Version
+			if strings.HasSuffix(version, ".*") && (spec.Operator == "==" || spec.Operator == "!=") {
+				spec.wildcard = true
+				version = strings.TrimSuffix(version, ".*")
+			}
+			v, err := ParseVersion(version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version specifier %q: %w", clause, err)
+			}
+			if spec.Operator == "~=" && len(v.Release) < 2 {
+				return nil, fmt.Errorf("invalid version specifier %q: ~= requires at least two release segments", clause)
+			}
+			spec.version = v
+		}
+
+		set = append(set, spec)
+	}
+	return set, nil
+}
+
+// String returns the specifiers joined by commas
+func (s SpecifierSet) String() string {
+	parts := make([]string, len(s))
+	for i, spec := range s {
+		parts[i] = spec.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// String returns the specifier, e.g. ">=3.10"
+func (s *Specifier) String() string {
+	return s.Operator + s.Version
+}
+
+// Contains reports whether v satisfies every specifier in the set.
+// Pre-releases only match when a specifier explicitly names one.
+func (s SpecifierSet) Contains(v *Version) bool {
+	if v.IsPrerelease() && !s.allowsPrereleases() {
+		return false
+	}
+	for _, spec := range s {
+		if !spec.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsPrereleases reports whether any specifier mentions a pre-release
+func (s SpecifierSet) allowsPrereleases() bool {
+	for _, spec := range s {
+		if spec.version != nil && spec.version.IsPrerelease() {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether v satisfies the specifier
+func (s *Specifier) Contains(v *Version) bool {
+	if s.Operator == "===" {
+		return strings.EqualFold(strings.TrimSpace(v.original), s.Version) || strings.EqualFold(v.String(), s.Version)
+	}
+
+	switch s.Operator {
+	case "==":
+		return s.matchesEqual(v)
+	case "!=":
+		return !s.matchesEqual(v)
+	case "<=":
+		return v.Public().Compare(s.version) <= 0
+	case ">=":
+		return v.Public().Compare(s.version) >= 0
+	case "<":
+		// "<V" excludes pre-releases of V unless V itself is one
+		if v.Compare(s.version) >= 0 {
+			return false
+		}
+		if !s.version.IsPrerelease() && v.IsPrerelease() && v.BaseVersion().Compare(s.version.BaseVersion()) == 0 {
+			return false
+		}
+		return true
+	case ">":
+		// ">V" excludes post-releases and local versions of V
+		if v.Compare(s.version) <= 0 {
+			return false
+		}
+		if !s.version.IsPostRelease() && v.IsPostRelease() && v.BaseVersion().Compare(s.version.BaseVersion()) == 0 {
+			return false
+		}
+		if v.Local != "" && v.Public().Compare(s.version) == 0 {
+			return false
+		}
+		return true
+	case "~=":
+		// "~=X.Y.Z" means ">=X.Y.Z, ==X.Y.*"
+		prefix := &Specifier{Operator: "==", wildcard: true, version: &Version{
+			Epoch:   s.version.Epoch,
+			Release: s.version.Release[:len(s.version.Release)-1],
+			Post:    -1,
+			Dev:     -1,
+		}}
+		return v.Public().Compare(s.version) >= 0 && prefix.matchesEqual(v)
+	}
+	return false
+}
+
+// matchesEqual implements "==", including prefix matching for wildcards
+func (s *Specifier) matchesEqual(v *Version) bool {
+	if !s.wildcard {
+		candidate := v
+		if s.version.Local == "" {
+			candidate = v.Public()
+		}
+		return candidate.Compare(s.version) == 0
+	}
+
+	if v.Epoch != s.version.Epoch {
+		return false
+	}
+	for i, part := range s.version.Release {
+		var actual int
+		if i < len(v.Release) {
+			actual = v.Release[i]
+		}
+		if actual != part {
+			return false
+		}
+	}
+	return true
+}
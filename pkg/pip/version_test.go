@@ -0,0 +1,126 @@
+package pip
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1.0", "1.0"},
+		{"v2.31.0", "2.31.0"},
+		{"1!2.0", "1!2.0"},
+		{"1.0alpha1", "1.0a1"},
+		{"1.0-beta.2", "1.0b2"},
+		{"1.0c3", "1.0rc3"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.rev2", "1.0.post2"},
+		{"1.0.dev", "1.0.dev0"},
+		{"1.0rc1.post2.dev3", "1.0rc1.post2.dev3"},
+		{"1.0+Ubuntu-1", "1.0+ubuntu.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := ParseVersion(tt.input)
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) error: %v", tt.input, err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("ParseVersion(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "abc", "1.0.x", "1..0"} {
+		if _, err := ParseVersion(invalid); err == nil {
+			t.Errorf("ParseVersion(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// Each version sorts strictly after the previous one
+	ordered := []string{
+		"1.0.dev0",
+		"1.0a1.dev1",
+		"1.0a1",
+		"1.0a2",
+		"1.0b1",
+		"1.0rc1",
+		"1.0",
+		"1.0+local.1",
+		"1.0+local.2",
+		"1.0.post1.dev0",
+		"1.0.post1",
+		"1.1",
+		"1.10",
+		"2!0.1",
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		a, b := MustParseVersion(ordered[i-1]), MustParseVersion(ordered[i])
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+
+	if MustParseVersion("1.0").Compare(MustParseVersion("1.0.0")) != 0 {
+		t.Error("trailing zeros should not affect ordering")
+	}
+}
+
+func TestSpecifierSet(t *testing.T) {
+	tests := []struct {
+		spec    string
+		version string
+		want    bool
+	}{
+		{">=3.10,<3.13", "3.12.1", true},
+		{">=3.10,<3.13", "3.13.0", false},
+		{">=3.10,<3.13", "3.9.18", false},
+		{"3.11", "3.11.7", true},
+		{"3.11", "3.12.0", false},
+		{"==3.11.*", "3.11.0", true},
+		{"!=3.11.*", "3.11.2", false},
+		{"~=2.2", "2.9", true},
+		{"~=2.2", "3.0", false},
+		{"~=1.4.5", "1.4.9", true},
+		{"~=1.4.5", "1.5.0", false},
+		{"==1.0", "1.0+local", true},
+		{"==1.0+local", "1.0", false},
+		{"<3.13", "3.13.0rc1", false},
+		{">=3.13.0rc1", "3.13.0rc2", true},
+		{">=3.12", "3.13.0b1", false},
+		{">1.0", "1.0.post1", false},
+		{">1.0.post1", "1.0.post2", true},
+		{"<=2.0", "2.0", true},
+		{"===1.0.0", "1.0.0", true},
+		{"===1.0", "1.0.0", false},
+		{"", "1.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec+" "+tt.version, func(t *testing.T) {
+			set, err := ParseSpecifierSet(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSpecifierSet(%q) error: %v", tt.spec, err)
+			}
+			v, err := ParseVersion(tt.version)
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) error: %v", tt.version, err)
+			}
+			if got := set.Contains(v); got != tt.want {
+				t.Errorf("%q.Contains(%q) = %v, want %v", tt.spec, tt.version, got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{">=abc", "~=1", "=>1.0"} {
+		if _, err := ParseSpecifierSet(invalid); err == nil {
+			t.Errorf("ParseSpecifierSet(%q) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package pip
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WheelOptions controls BuildWheels
+type WheelOptions struct {
+	WheelDir     string   `json:"wheel_dir"`              // directory the wheels are written to, created if missing
+	Requirements []string `json:"requirements,omitempty"` // requirement files to build (-r)
+	Projects     []string `json:"projects,omitempty"`     // local project directories to build
+
+	NoDeps           bool              `json:"no_deps,omitempty"`
+	NoBuildIsolation bool              `json:"no_build_isolation,omitempty"` // build with the packages already installed
+	Index            string            `json:"index,omitempty"`              // custom index URL
+	Options          map[string]string `json:"options,omitempty"`            // additional pip options
+	Handler          OutputHandler     `json:"-"`                            // receives output lines as they are produced
+}
+
+// BuildWheels builds wheels for packages, requirement files and local
+// projects, and their dependencies, into opts.WheelDir with "pip wheel".
+// It returns every wheel written, with Built set for wheels built from a
+// source distribution or project rather than downloaded as wheels. When a
+// build fails the error has type build_failed and the wheels that were
+// written are still returned.
+func (m *Manager) BuildWheels(pkgs []*PackageSpec, opts *WheelOptions) ([]*Artifact, error) {
+	if opts == nil || opts.WheelDir == "" {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPath),
+			Message: "wheel directory cannot be empty",
+		}
+	}
+	if len(pkgs) == 0 && len(opts.Requirements) == 0 && len(opts.Projects) == 0 {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPackageSpec),
+			Message: "no packages to build",
+		}
+	}
+	for _, pkg := range pkgs {
+		if err := m.validatePackageSpec(pkg); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range opts.Requirements {
+		if err := m.validateRequirementsFile(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, project := range opts.Projects {
+		if info, err := os.Stat(project); err != nil || !info.IsDir() {
+			return nil, &PipError{
+				Type:    string(ErrorTypeInvalidPath),
+				Message: fmt.Sprintf("project directory not found: %s", project),
+			}
+		}
+	}
+
+	wheelDir, err := filepath.Abs(opts.WheelDir)
+	if err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypeInvalidPath),
+			Message: fmt.Sprintf("invalid wheel directory: %v", err),
+		}
+	}
+
+	args, err := buildWheelArgs(wheelDir, pkgs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logInfo("Building wheels for %d packages in %s", len(pkgs)+len(opts.Requirements)+len(opts.Projects), wheelDir)
+
+	pipPath, err := m.findPipExecutable()
+	if err != nil {
+		return nil, ErrPipNotInstalled
+	}
+
+	if err := m.mkdirAll(wheelDir, 0755); err != nil {
+		return nil, &PipError{
+			Type:    string(ErrorTypePermissionDenied),
+			Message: fmt.Sprintf("failed to create wheel directory: %v", err),
+		}
+	}
+	if m.planPipCommand(pipPath, args, "build wheels") {
+		return nil, nil
+	}
+
+	var lines []string
+	collect := func(line OutputLine) {
+		lines = append(lines, line.Text)
+		if opts.Handler != nil {
+			opts.Handler(line)
+		}
+	}
+
+	_, err = m.executePipCommandStreaming(pipPath, args, collect, func() { lines = nil })
+	if pipErr, ok := err.(*PipError); ok && pipErr.Type == string(ErrorTypeCommandFailed) && buildFailed(lines) {
+		pipErr.Type = string(ErrorTypeBuildFailed)
+	}
+	return savedArtifacts(wheelDir, lines), err
+}
+
+// buildWheelArgs builds the pip wheel command writing to wheelDir
+func buildWheelArgs(wheelDir string, pkgs []*PackageSpec, opts *WheelOptions) ([]string, error) {
+	args := []string{"wheel", "--wheel-dir", wheelDir}
+
+	for _, pkg := range pkgs {
+		if pkg.Editable {
+			args = append(args, "--editable")
+		}
+		args = append(args, requirementString(pkg))
+	}
+	index, options, err := mergeSpecOptions(pkgs, opts.Index, opts.Options, "WheelOptions")
+	if err != nil {
+		return nil, err
+	}
+
+	args = append(args, opts.Projects...)
+	for _, path := range opts.Requirements {
+		args = append(args, "-r", path)
+	}
+	if opts.NoDeps {
+		args = append(args, "--no-deps")
+	}
+	if opts.NoBuildIsolation {
+		args = append(args, "--no-build-isolation")
+	}
+	if index != "" {
+		args = append(args, "--index-url", index)
+	}
+
+	return appendSortedOptions(args, options), nil
+}
+
+// buildFailed reports whether pip output mentions a failed wheel build
+func buildFailed(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Failed to build ") ||
+			strings.HasPrefix(trimmed, "ERROR: Failed to build ") ||
+			strings.HasPrefix(trimmed, "ERROR: Failed building wheel for ") ||
+			strings.HasPrefix(trimmed, "ERROR: Could not build wheels for ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package pip
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildWheels(t *testing.T) {
+	dir := t.TempDir()
+	wheelDir := filepath.Join(dir, "wheels")
+	project := filepath.Join(dir, "myproject")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "wheel", "--wheel-dir", wheelDir, "pyyaml==6.0.1", project, "--no-deps", "--no-build-isolation").Respond(
+		"Collecting pyyaml==6.0.1\n"+
+			"  Downloading PyYAML-6.0.1.tar.gz (125 kB)\n"+
+			"Processing "+project+"\n"+
+			"Collecting six\n"+
+			"  Downloading six-1.16.0-py2.py3-none-any.whl (11 kB)\n"+
+			"Saved ./wheels/six-1.16.0-py2.py3-none-any.whl\n"+
+			"Building wheels for collected packages: pyyaml, myproject\n"+
+			"  Building wheel for pyyaml (pyproject.toml): started\n"+
+			"  Created wheel for pyyaml: filename=PyYAML-6.0.1-cp311-cp311-linux_x86_64.whl size=738912 sha256=0a1b\n"+
+			"  Stored in directory: /root/.cache/pip/wheels/ab/cd\n"+
+			"  Created wheel for myproject: filename=myproject-0.1.0-2-py3-none-any.whl size=1200 sha256=9f8e\n"+
+			"Successfully built pyyaml myproject\n",
+		"", 0)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	wheels, err := manager.BuildWheels([]*PackageSpec{{Name: "pyyaml", Version: "==6.0.1"}}, &WheelOptions{
+		WheelDir:         wheelDir,
+		Projects:         []string{project},
+		NoDeps:           true,
+		NoBuildIsolation: true,
+	})
+	if err != nil {
+		t.Fatalf("BuildWheels() error: %v", err)
+	}
+
+	var got []string
+	for _, wheel := range wheels {
+		got = append(got, wheel.Filename)
+		if wheel.Path != filepath.Join(wheelDir, wheel.Filename) {
+			t.Errorf("%s path = %s", wheel.Filename, wheel.Path)
+		}
+	}
+	want := []string{
+		"six-1.16.0-py2.py3-none-any.whl",
+		"PyYAML-6.0.1-cp311-cp311-linux_x86_64.whl",
+		"myproject-0.1.0-2-py3-none-any.whl",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("wheels = %v, want %v", got, want)
+	}
+
+	if wheels[0].Built || !wheels[1].Built || !wheels[2].Built {
+		t.Errorf("built = %v %v %v, want false true true", wheels[0].Built, wheels[1].Built, wheels[2].Built)
+	}
+	if wheels[1].Name != "PyYAML" || wheels[1].Version != "6.0.1" || wheels[1].PlatformTags[0] != "linux_x86_64" {
+		t.Errorf("pyyaml = %+v", wheels[1])
+	}
+	if wheels[2].BuildTag != "2" {
+		t.Errorf("myproject build tag = %q", wheels[2].BuildTag)
+	}
+}
+
+func TestBuildWheelsFromWheelCache(t *testing.T) {
+	// Output of pip 23.2 building demo from an sdist in a --find-links
+	// directory, then again from its wheel cache, and downloading the
+	// ready-made wheel over HTTP
+	outputs := []struct {
+		name   string
+		output string
+		built  bool
+	}{
+		{"built", "Looking in links: /tmp/pipcap/links\n" +
+			"Processing ./links/demo-1.0.tar.gz\n" +
+			"  Preparing metadata (pyproject.toml): started\n" +
+			"  Preparing metadata (pyproject.toml): finished with status 'done'\n" +
+			"Building wheels for collected packages: demo\n" +
+			"  Building wheel for demo (pyproject.toml): started\n" +
+			"  Building wheel for demo (pyproject.toml): finished with status 'done'\n" +
+			"  Created wheel for demo: filename=demo-1.0-py3-none-any.whl size=890 sha256=65527df2d7909a79600761ccbddc1858d8e47df07375e1fe2b4454a85a1f6c67\n" +
+			"  Stored in directory: /tmp/pipcap/cache/wheels/b6/e8/fe/f688b6a368318e61e361b44f1a221921c4bcf252dddfb09981\n" +
+			"Successfully built demo\n", true},
+		{"wheel cache", "Looking in links: /tmp/pipcap/links\n" +
+			"Collecting demo==1.0\n" +
+			"  Using cached demo-1.0-py3-none-any.whl\n" +
+			"Saved ./out2/demo-1.0-py3-none-any.whl\n", true},
+		{"downloaded", "Looking in links: http://127.0.0.1:18765/\n" +
+			"Collecting demo==1.0\n" +
+			"  Downloading http://127.0.0.1:18765/demo-1.0-py3-none-any.whl (890 bytes)\n" +
+			"Saved ./o3/demo-1.0-py3-none-any.whl\n", false},
+		{"older pip wheel cache", "Processing /tmp/pipcap/cache/wheels/b6/e8/fe/f688b6a368318e61e361b44f1a221921c4bcf252dddfb09981/demo-1.0-py3-none-any.whl\n" +
+			"Saved ./out2/demo-1.0-py3-none-any.whl\n", true},
+	}
+
+	for _, tt := range outputs {
+		t.Run(tt.name, func(t *testing.T) {
+			wheelDir := t.TempDir()
+
+			runner := NewFakeRunner()
+			runner.On("python", "-m", "pip", "wheel", "--wheel-dir", wheelDir, "demo==1.0").Respond(tt.output, "", 0)
+
+			manager := NewManager(nil)
+			manager.SetCommandRunner(runner)
+
+			wheels, err := manager.BuildWheels([]*PackageSpec{{Name: "demo", Version: "==1.0"}}, &WheelOptions{WheelDir: wheelDir})
+			if err != nil {
+				t.Fatalf("BuildWheels() error: %v", err)
+			}
+			if len(wheels) != 1 || wheels[0].Filename != "demo-1.0-py3-none-any.whl" {
+				t.Fatalf("wheels = %v, want demo-1.0-py3-none-any.whl", wheels)
+			}
+			if wheels[0].Built != tt.built {
+				t.Errorf("Built = %v, want %v", wheels[0].Built, tt.built)
+			}
+		})
+	}
+}
+
+func TestBuildWheelsFailure(t *testing.T) {
+	wheelDir := t.TempDir()
+
+	runner := NewFakeRunner()
+	runner.On("python", "-m", "pip", "wheel", "...").Respond(
+		"Saved ./six-1.16.0-py2.py3-none-any.whl\n"+
+			"Building wheels for collected packages: broken\n"+
+			"  Building wheel for broken (setup.py): finished with status 'error'\n"+
+			"Failed to build broken\n",
+		"ERROR: Failed to build one or more wheels\n", 1)
+
+	manager := NewManager(nil)
+	manager.SetCommandRunner(runner)
+
+	wheels, err := manager.BuildWheels([]*PackageSpec{{Name: "broken"}}, &WheelOptions{WheelDir: wheelDir})
+	if !IsErrorType(err, ErrorTypeBuildFailed) {
+		t.Fatalf("error = %v, want build_failed", err)
+	}
+	if len(wheels) != 1 || wheels[0].Name != "six" {
+		t.Errorf("wheels = %+v, want the saved six wheel", wheels)
+	}
+}
+
+func TestBuildWheelsValidation(t *testing.T) {
+	manager := NewManager(nil)
+	manager.SetCommandRunner(NewFakeRunner())
+
+	tests := []struct {
+		name string
+		pkgs []*PackageSpec
+		opts *WheelOptions
+		want ErrorType
+	}{
+		{"no wheel dir", []*PackageSpec{{Name: "six"}}, nil, ErrorTypeInvalidPath},
+		{"nothing to build", nil, &WheelOptions{WheelDir: "out"}, ErrorTypeInvalidPackageSpec},
+		{"missing project", nil, &WheelOptions{WheelDir: "out", Projects: []string{"/nonexistent/project"}}, ErrorTypeInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := manager.BuildWheels(tt.pkgs, tt.opts); !IsErrorType(err, tt.want) {
+				t.Errorf("error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}